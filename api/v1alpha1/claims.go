@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InterfaceClaimConflict is returned when an interface is already claimed on a node by another monitor.
+// +kubebuilder:object:generate=false
+type InterfaceClaimConflict struct {
+	Node      string
+	Interface string
+	Monitor   string
+}
+
+func (e *InterfaceClaimConflict) Error() string {
+	return fmt.Sprintf("interface %s is already claimed on node %s by PFLACPMonitor %s", e.Interface, e.Node, e.Monitor)
+}
+
+// MonitorKey returns the namespace/name of a monitor, as recorded in the interface claims.
+func MonitorKey(pfMonitor *PFLACPMonitor) string {
+	return pfMonitor.Namespace + "/" + pfMonitor.Name
+}
+
+// NodeStateInventory returns the interfaces found on each node by the node agent.
+// Node states that were never synced are left out.
+func NodeStateInventory(nodeStateList *PFLACPNodeStateList) map[string][]HostInterface {
+	inventory := make(map[string][]HostInterface, len(nodeStateList.Items))
+	for _, nodeState := range nodeStateList.Items {
+		if nodeState.Status.LastUpdateTime == nil {
+			continue
+		}
+		ifaces := make([]HostInterface, 0, len(nodeState.Status.Interfaces))
+		for _, pf := range nodeState.Status.Interfaces {
+			ifaces = append(ifaces, pf.HostInterface)
+		}
+		inventory[nodeState.Name] = ifaces
+	}
+
+	return inventory
+}
+
+// NodeInterfaceClaims returns the names of the interfaces the monitor claims on each node of nodeList it selects,
+// sorted by name. Selectors are resolved against the inventory of the node, so they claim nothing on the nodes
+// without inventory.
+func NodeInterfaceClaims(pfMonitor *PFLACPMonitor, nodeList *corev1.NodeList, inventory map[string][]HostInterface) (map[string][]string, error) {
+	claims := make(map[string][]string)
+	for _, node := range nodeList.Items {
+		entries, _, err := NodeInterfaces(pfMonitor, &node)
+		if err != nil {
+			return nil, err
+		}
+		if entries == nil {
+			continue
+		}
+
+		names, err := ResolveInterfaces(entries, inventory[node.Name])
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		sort.Strings(names)
+		claims[node.Name] = names
+	}
+
+	return claims, nil
+}
+
+// ClaimConflict checks that none of the interfaces claimed by the monitor is claimed in claimList by another
+// monitor taking precedence over it, as defined by MonitorPrecedes. It returns an *InterfaceClaimConflict for
+// the first interface that is. The interfaces claimed by the monitors it takes precedence over can be taken over.
+func ClaimConflict(pfMonitor *PFLACPMonitor, claims map[string][]string, claimList *PFInterfaceClaimList) error {
+	for _, claim := range claimList.Items {
+		if err := checkNodeClaims(claim.Name, claims[claim.Name], claim.Spec.Claims, claimPrecedes(pfMonitor)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// claimPrecedes returns a function checking whether a claim is held by another monitor taking precedence over the monitor.
+func claimPrecedes(pfMonitor *PFLACPMonitor) func(InterfaceClaim) bool {
+	key := MonitorKey(pfMonitor)
+	rank := rankOf(pfMonitor)
+	return func(claim InterfaceClaim) bool {
+		return claim.Monitor != key && claimRank(claim).precedes(rank)
+	}
+}
+
+// claimRank returns the rank of the monitor holding a claim.
+func claimRank(claim InterfaceClaim) monitorRank {
+	namespace, name, _ := strings.Cut(claim.Monitor, "/")
+	rank := monitorRank{priority: claim.Priority, name: name, namespace: namespace}
+	if claim.MonitorCreationTimestamp != nil {
+		rank.created = *claim.MonitorCreationTimestamp
+	}
+	return rank
+}
+
+// checkNodeClaims checks that none of the interface names is claimed on a node by a claim matching conflicts.
+func checkNodeClaims(node string, names []string, claims []InterfaceClaim, conflicts func(InterfaceClaim) bool) error {
+	for _, claim := range claims {
+		if slices.Contains(names, claim.Interface) && conflicts(claim) {
+			return &InterfaceClaimConflict{Node: node, Interface: claim.Interface, Monitor: claim.Monitor}
+		}
+	}
+
+	return nil
+}
+
+// SetInterfaceClaims replaces the interfaces claimed by the monitor in the claims of a node by names, taking over
+// the ones claimed by the monitors it takes precedence over. It returns an *InterfaceClaimConflict, leaving the
+// claims untouched, if one of them is claimed by a monitor taking precedence over it.
+func SetInterfaceClaims(claim *PFInterfaceClaim, pfMonitor *PFLACPMonitor, names []string) error {
+	if err := checkNodeClaims(claim.Name, names, claim.Spec.Claims, claimPrecedes(pfMonitor)); err != nil {
+		return err
+	}
+
+	key := MonitorKey(pfMonitor)
+	claims := make([]InterfaceClaim, 0, len(claim.Spec.Claims)+len(names))
+	for _, c := range claim.Spec.Claims {
+		if c.Monitor != key && !slices.Contains(names, c.Interface) {
+			claims = append(claims, c)
+		}
+	}
+	for _, name := range names {
+		c := InterfaceClaim{Interface: name, Monitor: key, Priority: pfMonitor.Spec.Priority}
+		if !pfMonitor.CreationTimestamp.IsZero() {
+			c.MonitorCreationTimestamp = pfMonitor.CreationTimestamp.DeepCopy()
+		}
+		claims = append(claims, c)
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Interface < claims[j].Interface
+	})
+
+	if len(claims) == 0 {
+		claims = nil
+	}
+	claim.Spec.Claims = claims
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Interface claims", func() {
+	var (
+		monitor  *PFLACPMonitor
+		nodeList *corev1.NodeList
+	)
+
+	BeforeEach(func() {
+		monitor = &PFLACPMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: PFLACPMonitorSpec{
+				Interfaces:   []string{"eth1", "driver=ice"},
+				NodeSelector: map[string]string{"role": "worker"},
+			},
+		}
+
+		nodeList = &corev1.NodeList{
+			Items: []corev1.Node{
+				newNode("worker-0", map[string]string{"role": "worker"}),
+				newNode("worker-1", map[string]string{"role": "worker"}),
+				newNode("master-0", map[string]string{"role": "master"}),
+			},
+		}
+	})
+
+	It("claims the resolved interfaces of the selected nodes", func() {
+		inventory := map[string][]HostInterface{
+			"worker-0": {{Name: "ens1f0", Driver: "ice"}, {Name: "ens2f0", Driver: "mlx5_core"}},
+		}
+
+		claims, err := NodeInterfaceClaims(monitor, nodeList, inventory)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims).To(Equal(map[string][]string{
+			"worker-0": {"ens1f0", "eth1"},
+			"worker-1": {"eth1"},
+		}))
+	})
+
+	It("detects interfaces claimed by another monitor taking precedence", func() {
+		claims := map[string][]string{"worker-0": {"eth0", "eth1"}}
+		claimList := &PFInterfaceClaimList{
+			Items: []PFInterfaceClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+				Spec: PFInterfaceClaimSpec{Claims: []InterfaceClaim{
+					{Interface: "eth0", Monitor: "default/monitor"},
+					{Interface: "eth1", Monitor: "other/monitor", Priority: 1},
+				}},
+			}},
+		}
+
+		err := ClaimConflict(monitor, claims, claimList)
+		var conflict *InterfaceClaimConflict
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+		Expect(conflict).To(Equal(&InterfaceClaimConflict{Node: "worker-0", Interface: "eth1", Monitor: "other/monitor"}))
+
+		claims["worker-0"] = []string{"eth0"}
+		Expect(ClaimConflict(monitor, claims, claimList)).To(Succeed())
+	})
+
+	It("replaces the interfaces claimed by a monitor", func() {
+		other := &PFLACPMonitor{ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "other"}}
+		claim := &PFInterfaceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+			Spec: PFInterfaceClaimSpec{Claims: []InterfaceClaim{
+				{Interface: "eth0", Monitor: "default/monitor"},
+				{Interface: "eth2", Monitor: "other/monitor", Priority: 1},
+			}},
+		}
+
+		Expect(SetInterfaceClaims(claim, monitor, []string{"eth3", "eth1"})).To(Succeed())
+		Expect(claim.Spec.Claims).To(Equal([]InterfaceClaim{
+			{Interface: "eth1", Monitor: "default/monitor"},
+			{Interface: "eth2", Monitor: "other/monitor", Priority: 1},
+			{Interface: "eth3", Monitor: "default/monitor"},
+		}))
+
+		Expect(SetInterfaceClaims(claim, monitor, []string{"eth2"})).To(HaveOccurred())
+		Expect(claim.Spec.Claims).To(HaveLen(3))
+
+		Expect(SetInterfaceClaims(claim, monitor, nil)).To(Succeed())
+		other.Spec.Priority = 1
+		Expect(SetInterfaceClaims(claim, other, nil)).To(Succeed())
+		Expect(claim.Spec.Claims).To(BeNil())
+	})
+
+	It("takes over the interfaces claimed by the monitors it takes precedence over", func() {
+		created := metav1.NewTime(time.Date(2024, 8, 9, 21, 48, 3, 0, time.UTC))
+		monitor.CreationTimestamp = created
+		claim := &PFInterfaceClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+			Spec: PFInterfaceClaimSpec{Claims: []InterfaceClaim{
+				{Interface: "eth0", Monitor: "other/newer", MonitorCreationTimestamp: &metav1.Time{Time: created.Add(time.Hour)}},
+				{Interface: "eth1", Monitor: "other/older", MonitorCreationTimestamp: &metav1.Time{Time: created.Add(-time.Hour)}},
+			}},
+		}
+		claimList := &PFInterfaceClaimList{Items: []PFInterfaceClaim{*claim}}
+
+		Expect(ClaimConflict(monitor, map[string][]string{"worker-0": {"eth0"}}, claimList)).To(Succeed())
+		Expect(ClaimConflict(monitor, map[string][]string{"worker-0": {"eth1"}}, claimList)).To(HaveOccurred())
+
+		Expect(SetInterfaceClaims(claim, monitor, []string{"eth0"})).To(Succeed())
+		Expect(claim.Spec.Claims).To(Equal([]InterfaceClaim{
+			{Interface: "eth0", Monitor: "default/monitor", MonitorCreationTimestamp: &created},
+			{Interface: "eth1", Monitor: "other/older", MonitorCreationTimestamp: &metav1.Time{Time: created.Add(-time.Hour)}},
+		}))
+	})
+})
@@ -0,0 +1,201 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Interface selector keys accepted in the interface lists, in addition to literal interface names.
+// A selector entry has the form <key>=<value>.
+const (
+	// InterfaceSelectorPCIAddress selects the PFs whose PCI address matches a glob, e.g. pci=0000:3b:00.*
+	InterfaceSelectorPCIAddress = "pci"
+	// InterfaceSelectorID selects the PFs with a PCI vendor and device ID, e.g. id=8086:159b
+	InterfaceSelectorID = "id"
+	// InterfaceSelectorDriver selects the PFs bound to a kernel driver, e.g. driver=ice
+	InterfaceSelectorDriver = "driver"
+	// InterfaceSelectorBond selects the PFs enslaved to a bond, e.g. bond=bond0
+	InterfaceSelectorBond = "bond"
+)
+
+var pciIDRegexp = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{4}$`)
+
+// HostInterface describes a physical function of a node.
+type HostInterface struct {
+	// Name of the interface
+	Name string `json:"name"`
+
+	// PCI address of the interface
+	// +optional
+	PCIAddress string `json:"pciAddress,omitempty"`
+
+	// PCI vendor ID of the interface
+	// +optional
+	VendorID string `json:"vendorID,omitempty"`
+
+	// PCI device ID of the interface
+	// +optional
+	DeviceID string `json:"deviceID,omitempty"`
+
+	// Kernel driver bound to the interface
+	// +optional
+	Driver string `json:"driver,omitempty"`
+
+	// Name of the bond the interface is enslaved to
+	// +optional
+	BondMaster string `json:"bondMaster,omitempty"`
+}
+
+// InterfaceSelector selects the interfaces of a node by one of their properties.
+// +kubebuilder:object:generate=false
+type InterfaceSelector struct {
+	Key   string
+	Value string
+}
+
+// ParseInterfaceSelector parses an entry of an interface list.
+// It returns nil if the entry is a literal interface name.
+func ParseInterfaceSelector(entry string) (*InterfaceSelector, error) {
+	key, value, found := strings.Cut(entry, "=")
+	if !found {
+		return nil, nil
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("interface selector %q has an empty value", entry)
+	}
+
+	switch key {
+	case InterfaceSelectorPCIAddress:
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("interface selector %q has an invalid PCI address glob: %w", entry, err)
+		}
+	case InterfaceSelectorID:
+		if !pciIDRegexp.MatchString(value) {
+			return nil, fmt.Errorf("interface selector %q must have the form %s=<vendor>:<device> with 4 lowercase hex digits each", entry, InterfaceSelectorID)
+		}
+	case InterfaceSelectorDriver, InterfaceSelectorBond:
+	default:
+		return nil, fmt.Errorf("unknown interface selector %q, expected one of %s, %s, %s or %s", key,
+			InterfaceSelectorPCIAddress, InterfaceSelectorID, InterfaceSelectorDriver, InterfaceSelectorBond)
+	}
+
+	return &InterfaceSelector{Key: key, Value: value}, nil
+}
+
+// CanonicalInterface returns the canonical form of an entry of an interface list: surrounding
+// whitespace is removed and, for selectors, the key and PCI address or ID values are lowercased.
+func CanonicalInterface(entry string) string {
+	entry = strings.TrimSpace(entry)
+	key, value, found := strings.Cut(entry, "=")
+	if !found {
+		return entry
+	}
+
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if key == InterfaceSelectorPCIAddress || key == InterfaceSelectorID {
+		value = strings.ToLower(value)
+	}
+
+	return key + "=" + value
+}
+
+// CanonicalInterfaces returns the canonical form of the entries of an interface list, sorted.
+func CanonicalInterfaces(entries []string) []string {
+	if entries == nil {
+		return nil
+	}
+
+	canonical := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		canonical = append(canonical, CanonicalInterface(entry))
+	}
+	sort.Strings(canonical)
+
+	return canonical
+}
+
+// Matches checks whether the interface is selected.
+func (s *InterfaceSelector) Matches(iface HostInterface) bool {
+	switch s.Key {
+	case InterfaceSelectorPCIAddress:
+		matched, _ := path.Match(s.Value, iface.PCIAddress)
+		return iface.PCIAddress != "" && matched
+	case InterfaceSelectorID:
+		return s.Value == iface.VendorID+":"+iface.DeviceID
+	case InterfaceSelectorDriver:
+		return s.Value == iface.Driver
+	case InterfaceSelectorBond:
+		return s.Value == iface.BondMaster
+	}
+	return false
+}
+
+// HasInterfaceSelectors checks whether an interface list contains selectors.
+func HasInterfaceSelectors(entries []string) bool {
+	for _, entry := range entries {
+		if selector, err := ParseInterfaceSelector(entry); err != nil || selector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveInterfaces returns the interface names of an interface list on a node with the given interfaces.
+// Literal names are kept as is, while selectors are replaced by the names of the interfaces they match,
+// sorted by name. Each name is returned once.
+func ResolveInterfaces(entries []string, ifaces []HostInterface) ([]string, error) {
+	seen := make(map[string]struct{})
+	names := make([]string, 0, len(entries))
+	add := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	for _, entry := range entries {
+		selector, err := ParseInterfaceSelector(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if selector == nil {
+			add(entry)
+			continue
+		}
+
+		var matched []string
+		for _, iface := range ifaces {
+			if selector.Matches(iface) {
+				matched = append(matched, iface.Name)
+			}
+		}
+		sort.Strings(matched)
+		for _, name := range matched {
+			add(name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,74 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interface selectors", func() {
+	ifaces := []HostInterface{
+		{Name: "ens1f1", PCIAddress: "0000:3b:00.1", VendorID: "8086", DeviceID: "159b", Driver: "ice", BondMaster: "bond0"},
+		{Name: "ens1f0", PCIAddress: "0000:3b:00.0", VendorID: "8086", DeviceID: "159b", Driver: "ice", BondMaster: "bond0"},
+		{Name: "ens2f0", PCIAddress: "0000:5e:00.0", VendorID: "15b3", DeviceID: "1017", Driver: "mlx5_core"},
+	}
+
+	DescribeTable("ParseInterfaceSelector",
+		func(entry string, expected *InterfaceSelector, valid bool) {
+			selector, err := ParseInterfaceSelector(entry)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector).To(Equal(expected))
+		},
+		Entry("interface name", "eth0", nil, true),
+		Entry("PCI address glob", "pci=0000:3b:00.*", &InterfaceSelector{Key: "pci", Value: "0000:3b:00.*"}, true),
+		Entry("PCI ID", "id=8086:159b", &InterfaceSelector{Key: "id", Value: "8086:159b"}, true),
+		Entry("driver", "driver=ice", &InterfaceSelector{Key: "driver", Value: "ice"}, true),
+		Entry("bond", "bond=bond0", &InterfaceSelector{Key: "bond", Value: "bond0"}, true),
+		Entry("empty value", "driver=", nil, false),
+		Entry("unknown key", "mac=00:11:22:33:44:55", nil, false),
+		Entry("invalid glob", "pci=0000:3b:00.[", nil, false),
+		Entry("PCI ID without device", "id=8086", nil, false),
+		Entry("uppercase PCI ID", "id=8086:159B", nil, false),
+	)
+
+	DescribeTable("ResolveInterfaces",
+		func(entries []string, expected []string) {
+			names, err := ResolveInterfaces(entries, ifaces)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal(expected))
+		},
+		Entry("interface names are kept", []string{"eth0", "ens1f0"}, []string{"eth0", "ens1f0"}),
+		Entry("by PCI address", []string{"pci=0000:3b:00.*"}, []string{"ens1f0", "ens1f1"}),
+		Entry("by PCI ID", []string{"id=15b3:1017"}, []string{"ens2f0"}),
+		Entry("by driver", []string{"driver=mlx5_core"}, []string{"ens2f0"}),
+		Entry("by bond", []string{"bond=bond0"}, []string{"ens1f0", "ens1f1"}),
+		Entry("without duplicates", []string{"ens1f1", "driver=ice"}, []string{"ens1f1", "ens1f0"}),
+		Entry("nothing matched", []string{"driver=i40e"}, []string{}),
+	)
+
+	DescribeTable("CanonicalInterface",
+		func(entry, expected string) {
+			Expect(CanonicalInterface(entry)).To(Equal(expected))
+		},
+		Entry("interface name", " eth0\t", "eth0"),
+		Entry("interface name case is kept", "ETH0", "ETH0"),
+		Entry("selector", " driver = ice ", "driver=ice"),
+		Entry("selector key", "Driver=ice", "driver=ice"),
+		Entry("PCI address", "pci=0000:3B:00.*", "pci=0000:3b:00.*"),
+		Entry("PCI ID", "ID=8086:159B", "id=8086:159b"),
+		Entry("bond name case is kept", "bond=Bond0", "bond=Bond0"),
+	)
+
+	It("CanonicalInterfaces sorts the entries", func() {
+		Expect(CanonicalInterfaces([]string{" eth1", "eth0 ", "driver=ice"})).To(Equal([]string{"driver=ice", "eth0", "eth1"}))
+		Expect(CanonicalInterfaces(nil)).To(BeNil())
+	})
+
+	It("HasInterfaceSelectors detects selectors", func() {
+		Expect(HasInterfaceSelectors([]string{"eth0", "eth1"})).To(BeFalse())
+		Expect(HasInterfaceSelectors([]string{"eth0", "driver=ice"})).To(BeTrue())
+	})
+})
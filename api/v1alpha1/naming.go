@@ -0,0 +1,103 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// RelayNamePrefix prefixes the names of the resources deployed for the monitors.
+const RelayNamePrefix = "pf-status-relay"
+
+// NameHashLength is the length of the names suffixing the names of the DaemonSets of the node overrides and of
+// the interface selectors.
+const NameHashLength = 10
+
+// hashSuffix matches the names ending like the name of a DaemonSet of a node override or of interface selectors.
+var hashSuffix = regexp.MustCompile(fmt.Sprintf(`-(o-)?[%s]{%d}$`, rand.SafeEncodeString("0123456789"), NameHashLength))
+
+// DaemonSetName returns the name of the relay DaemonSet of the nodes of the monitor not matching any node
+// override, which is also the value of the app label of its pods.
+func DaemonSetName(pfMonitor *PFLACPMonitor) string {
+	return fmt.Sprintf("%s-ds-%s", RelayNamePrefix, pfMonitor.Name)
+}
+
+// OverrideDaemonSetName returns the name of the relay DaemonSet of the nodes of a node override of the monitor.
+// The override is identified by a hash, so that the name neither grows with the name of the override nor
+// matches the DaemonSet of another monitor.
+func OverrideDaemonSetName(pfMonitor *PFLACPMonitor, override string) string {
+	return fmt.Sprintf("%s-o-%s", DaemonSetName(pfMonitor), NameHash(override))
+}
+
+// SelectorDaemonSetName returns the name of the relay DaemonSet of the nodes resolving the interface selectors of
+// a group to the same interfaces, name being the name of the DaemonSet of the group.
+func SelectorDaemonSetName(name string, interfaces string) string {
+	return fmt.Sprintf("%s-%s", name, NameHash(interfaces))
+}
+
+// NameHash returns a name-safe hash of NameHashLength characters of a string.
+func NameHash(value string) string {
+	hasher := fnv.New32a()
+	hasher.Write([]byte(value))
+	return rand.SafeEncodeString(fmt.Sprintf("%0*d", NameHashLength, hasher.Sum32()))
+}
+
+// validateDaemonSetNames checks that the names of the relay DaemonSets of the monitor are valid label values,
+// distinct, and not derived from the names of the DaemonSets of other monitors.
+func validateDaemonSetNames(pfMonitor *PFLACPMonitor) field.ErrorList {
+	var allErrs field.ErrorList
+	namePath := field.NewPath("metadata").Child("name")
+	if hashSuffix.MatchString(pfMonitor.Name) {
+		allErrs = append(allErrs, field.Invalid(namePath, pfMonitor.Name, fmt.Sprintf("must not end with a dash "+
+			"followed by %d characters, optionally preceded by o-, which is reserved for the DaemonSets of the "+
+			"node overrides and interface selectors", NameHashLength)))
+	}
+
+	// The DaemonSets of interface selectors add a hash to the name of their group
+	selectorLength := len(SelectorDaemonSetName("", ""))
+	longest := len(DaemonSetName(pfMonitor))
+	if HasInterfaceSelectors(pfMonitor.Spec.Interfaces) {
+		longest += selectorLength
+	}
+	names := make(map[string]string, len(pfMonitor.Spec.NodeOverrides))
+	for i, override := range pfMonitor.Spec.NodeOverrides {
+		name := OverrideDaemonSetName(pfMonitor, override.Name)
+		if other, found := names[name]; found {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("nodeOverrides").Index(i).Child("name"),
+				override.Name, fmt.Sprintf("has the same DaemonSet name as the override %s, rename one of them", other)))
+		}
+		names[name] = override.Name
+
+		length := len(name)
+		if HasInterfaceSelectors(override.Interfaces) {
+			length += selectorLength
+		}
+		longest = max(longest, length)
+	}
+	if longest > validation.LabelValueMaxLength {
+		allErrs = append(allErrs, field.Invalid(namePath, pfMonitor.Name, fmt.Sprintf("is too long: the names of "+
+			"its DaemonSets would be %d characters long, beyond the limit of %d", longest, validation.LabelValueMaxLength)))
+	}
+
+	return allErrs
+}
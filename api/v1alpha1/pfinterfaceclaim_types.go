@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PFInterfaceClaimSpec defines the interfaces claimed on a node
+type PFInterfaceClaimSpec struct {
+	// Claims lists the interfaces of the node monitored by a PFLACPMonitor
+	// +listType=map
+	// +listMapKey=interface
+	// +optional
+	Claims []InterfaceClaim `json:"claims,omitempty"`
+}
+
+// InterfaceClaim records the PFLACPMonitor monitoring an interface
+type InterfaceClaim struct {
+	// Interface is the name of the claimed interface
+	Interface string `json:"interface"`
+
+	// Monitor is the PFLACPMonitor claiming the interface, as namespace/name
+	Monitor string `json:"monitor"`
+
+	// Priority is the priority of the monitor, deciding with its creation timestamp which monitor
+	// wins the interface
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// MonitorCreationTimestamp is the creation timestamp of the monitor
+	// +optional
+	MonitorCreationTimestamp *metav1.Time `json:"monitorCreationTimestamp,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// PFInterfaceClaim is the Schema for the pfinterfaceclaims API.
+// It is named after its node and records which PFLACPMonitor monitors each interface of the node,
+// whatever the namespace of the monitor. It is maintained by the operator
+type PFInterfaceClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PFInterfaceClaimSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PFInterfaceClaimList contains a list of PFInterfaceClaim
+type PFInterfaceClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PFInterfaceClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PFInterfaceClaim{}, &PFInterfaceClaimList{})
+}
@@ -17,17 +17,18 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
-// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
-
 // PFLACPMonitorSpec defines the desired state of PFLACPMonitor
 type PFLACPMonitorSpec struct {
 	// +kubebuilder:validation:MinItems=1
 
-	// List of interfaces to monitor
+	// List of interfaces to monitor. Besides interface names, it accepts selectors of the form
+	// pci=<PCI address glob>, id=<vendor>:<device>, driver=<driver> and bond=<bond>
 	Interfaces []string `json:"interfaces"`
 
 	// +kubebuilder:validation:Minimum=100
@@ -40,21 +41,332 @@ type PFLACPMonitorSpec struct {
 	// Selector to filter nodes
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Label selector to filter nodes, supporting set-based requirements.
+	// Nodes must match both nodeSelector and nodeLabelSelector.
+	// +optional
+	NodeLabelSelector *metav1.LabelSelector `json:"nodeLabelSelector,omitempty"`
+
+	// Interface overrides for groups of nodes. A selected node matching the node selector of an
+	// override monitors the interfaces of the first matching override instead of spec.interfaces.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NodeOverrides []NodeInterfaceOverride `json:"nodeOverrides,omitempty"`
+
+	// Priority of the monitor when it monitors the same interfaces as another monitor on a node.
+	// The monitor with the highest priority wins, then the oldest one, then the first one by name
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Scheduling, resources and metadata of the relay pods
+	// +optional
+	RelayPod *RelayPodTemplate `json:"relayPod,omitempty"`
+
+	// Rollout of the changes to the relay pods
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+
+	// Log level of the relay. Defaults to the default log level of the relay
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Temporary log level of the relay, replacing logLevel until it expires. The relay pods are
+	// rolled out again when it is set and once it expires, following the rollout settings: while
+	// the rollout is paused, neither is applied, and with canary nodes, the other nodes only get
+	// the new level after the soak time, which may exceed the duration of the override
+	// +optional
+	LogLevelOverride *LogLevelOverride `json:"logLevelOverride,omitempty"`
 }
 
+// LogLevelOverride defines a log level of the relay applied until a given time
+type LogLevelOverride struct {
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+
+	// Log level of the relay while the override is active
+	Level string `json:"level"`
+
+	// Time at which the override expires and logLevel applies again
+	Until metav1.Time `json:"until"`
+}
+
+// RolloutStrategy defines how the changes to the relay pods are rolled out
+type RolloutStrategy struct {
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+
+	// Type of the update strategy of the relay DaemonSets. With OnDelete, the relay pods are only
+	// replaced when they are deleted. Defaults to RollingUpdate
+	// +optional
+	Type appsv1.DaemonSetUpdateStrategyType `json:"type,omitempty"`
+
+	// Maximum number or percentage of selected nodes whose relay pod can be unavailable during a
+	// rolling update. Defaults to 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Maximum number or percentage of selected nodes that can run an updated relay pod next to the
+	// old one during a rolling update. Defaults to 0. It is not supported with canary nodes
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// Paused holds the changes to the existing relay pods until it is unset
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Canary nodes receiving the changes first during a rolling update
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// CanaryStrategy defines the nodes receiving the changes to the relay pods first
+type CanaryStrategy struct {
+	// Selector of the canary nodes among the selected nodes
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+
+	// +kubebuilder:default:="5m"
+
+	// Time the relay pods of the canary nodes must stay ready with the changes before they are
+	// rolled out to the other nodes
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// RelayPodTemplate defines the scheduling, resources and metadata of the relay pods
+type RelayPodTemplate struct {
+	// Labels added to the relay pods. The labels set by the operator take precedence
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to the relay pods
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Tolerations of the relay pods, for example to run on tainted control-plane nodes
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity of the relay pods. A node affinity further restricts the nodes selected by the monitor
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Priority class of the relay pods. Defaults to system-node-critical, so that the relay is
+	// among the last pods evicted under node pressure
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Compute resources of the relay container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// NodeInterfaceOverride defines the interfaces to monitor on a group of nodes
+type NodeInterfaceOverride struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+
+	// Name of the override
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinProperties=1
+
+	// Selector of the nodes the override applies to
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// +kubebuilder:validation:MinItems=1
+
+	// List of interfaces to monitor on the nodes, with the same syntax as spec.interfaces
+	Interfaces []string `json:"interfaces"`
+}
+
+// Condition types reported in PFLACPMonitorStatus.
+const (
+	// ConditionAvailable indicates that a ready pf-status-relay pod runs on every selected node.
+	ConditionAvailable = "Available"
+	// ConditionProgressing indicates that the pf-status-relay DaemonSet is being rolled out.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded indicates that the monitor could not be reconciled or its relay pods are failing.
+	ConditionDegraded = "Degraded"
+	// ConditionInterfaceConflict indicates that the monitor claims interfaces already monitored by another PFLACPMonitor on the same nodes.
+	ConditionInterfaceConflict = "InterfaceConflict"
+	// ConditionMissingInterfaces indicates that monitored interfaces were not found on some selected nodes by the node agent.
+	ConditionMissingInterfaces = "MissingInterfaces"
+)
+
+// Condition reasons reported in PFLACPMonitorStatus.
+const (
+	ReasonAsExpected              = "AsExpected"
+	ReasonDaemonSetSyncFailed     = "DaemonSetSyncFailed"
+	ReasonDaemonSetDeleteFailed   = "DaemonSetDeleteFailed"
+	ReasonImageNotConfigured      = "ImageNotConfigured"
+	ReasonNetworkPolicySyncFailed = "NetworkPolicySyncFailed"
+	ReasonInterfacesInUse         = "InterfacesInUse"
+	ReasonInterfaceClaimFailed    = "InterfaceClaimFailed"
+	ReasonNoConflict              = "NoConflict"
+	ReasonListFailed              = "ListFailed"
+	ReasonRollingOut              = "RollingOut"
+	ReasonRolloutPaused           = "RolloutPaused"
+	ReasonRelayPodsReady          = "RelayPodsReady"
+	ReasonRelayPodsUnavailable    = "RelayPodsUnavailable"
+	ReasonInterfacesUnresolved    = "InterfacesUnresolved"
+	ReasonInterfacesNotFound      = "InterfacesNotFound"
+	ReasonAllInterfacesFound      = "AllInterfacesFound"
+)
+
+// Rollout phases reported in RolloutStatus.
+const (
+	RolloutPhasePaused     = "Paused"
+	RolloutPhaseCanary     = "Canary"
+	RolloutPhaseSoaking    = "Soaking"
+	RolloutPhaseRollingOut = "RollingOut"
+	RolloutPhaseComplete   = "Complete"
+)
+
+// Reasons of the events recorded for a PFLACPMonitor.
+const (
+	EventReasonInterfaceConflict  = "InterfaceConflict"
+	EventReasonConflictResolved   = "ConflictResolved"
+	EventReasonDaemonSetCreated   = "DaemonSetCreated"
+	EventReasonDaemonSetUpdated   = "DaemonSetUpdated"
+	EventReasonDaemonSetDeleted   = "DaemonSetDeleted"
+	EventReasonCanaryPromoted     = "CanaryPromoted"
+	EventReasonImageNotConfigured = "ImageNotConfigured"
+)
+
 // PFLACPMonitorStatus defines the observed state of PFLACPMonitor
 type PFLACPMonitorStatus struct {
-	// Degraded indicates whether the monitor is in a degraded state
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the monitor state
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DesiredNumberScheduled is the number of nodes that should be running the relay pod
+	// +optional
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled,omitempty"`
+
+	// NumberReady is the number of nodes running a ready relay pod
+	// +optional
+	NumberReady int32 `json:"numberReady,omitempty"`
+
+	// UpdatedNumberScheduled is the number of nodes running the latest relay pod spec
+	// +optional
+	UpdatedNumberScheduled int32 `json:"updatedNumberScheduled,omitempty"`
+
+	// NumberUnavailable is the number of nodes that should be running the relay pod but have none available
+	// +optional
+	NumberUnavailable int32 `json:"numberUnavailable,omitempty"`
+
+	// UnreadyNodes lists the nodes whose relay pod is not ready
+	// +listType=map
+	// +listMapKey=nodeName
+	// +optional
+	UnreadyNodes []NodeRelayStatus `json:"unreadyNodes,omitempty"`
+
+	// Interfaces reports the LACP and VF link state of the monitored interfaces on each selected node,
+	// as observed by the node agent
+	// +listType=map
+	// +listMapKey=nodeName
+	// +listMapKey=name
+	// +optional
+	Interfaces []InterfaceStatus `json:"interfaces,omitempty"`
+
+	// WinningMonitor is the monitor taking precedence over this one for its interfaces, as namespace/name.
+	// It is set while the InterfaceConflict condition is True
+	// +optional
+	WinningMonitor string `json:"winningMonitor,omitempty"`
+
+	// Rollout reports the progress of the rollout of the changes to the relay pods
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStatus describes the progress of the rollout of the relay pods
+type RolloutStatus struct {
+	// +kubebuilder:validation:Enum=Paused;Canary;Soaking;RollingOut;Complete
+
+	// Phase of the rollout: Paused while the changes are held, Canary while the canary nodes are updated,
+	// Soaking while their relay pods must stay ready, RollingOut while the other nodes are updated,
+	// and Complete once every node runs the latest relay pod
+	Phase string `json:"phase"`
+
+	// Revision identifies the relay pods rolled out to the canary nodes
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// CanaryNodes is the number of canary nodes running a relay pod
+	// +optional
+	CanaryNodes int32 `json:"canaryNodes,omitempty"`
+
+	// UpdatedCanaryNodes is the number of canary nodes running a ready relay pod of the revision
+	// +optional
+	UpdatedCanaryNodes int32 `json:"updatedCanaryNodes,omitempty"`
+
+	// CanaryReadyTime is the time since which the relay pods of all the canary nodes are ready with the revision
+	// +optional
+	CanaryReadyTime *metav1.Time `json:"canaryReadyTime,omitempty"`
+
+	// Message is a human readable message about the progress of the rollout
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// LACP states reported in InterfaceStatus.
+const (
+	LACPStateUp      = "Up"
+	LACPStateDown    = "Down"
+	LACPStateUnknown = "Unknown"
+)
+
+// InterfaceStatus describes the state of a monitored interface on a node
+type InterfaceStatus struct {
+	// NodeName is the name of the node
+	NodeName string `json:"nodeName"`
+
+	// Name is the name of the interface
+	Name string `json:"name"`
+
+	// LACP is Up when the LACP partnership of the interface is synchronized and Down when it is not,
+	// in which case the relay forces the link state of its VFs down. It is Unknown when the interface
+	// is not enslaved to an 802.3ad bond
+	// +kubebuilder:validation:Enum=Up;Down;Unknown
+	LACP string `json:"lacp"`
+
+	// VFLinkState is the link state of the VFs of the interface: auto, enable or disable, or mixed when they differ
+	// +optional
+	VFLinkState string `json:"vfLinkState,omitempty"`
+
+	// LastTransitionTime is the last time the LACP or VF link state changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// NodeRelayStatus describes why the relay pod of a node is not ready
+type NodeRelayStatus struct {
+	// NodeName is the name of the node
+	NodeName string `json:"nodeName"`
+
+	// PodName is the name of the relay pod scheduled to the node
+	// +optional
+	PodName string `json:"podName,omitempty"`
+
+	// Reason is the waiting or terminated reason of the relay container
 	// +optional
-	Degraded bool `json:"degraded,omitempty"`
+	Reason string `json:"reason,omitempty"`
 
-	// Error message
+	// Message is a human readable message with details about the reason
 	// +optional
-	ErrorMessage string `json:"errorMessage,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 
 // PFLACPMonitor is the Schema for the pflacpmonitors API
 type PFLACPMonitor struct {
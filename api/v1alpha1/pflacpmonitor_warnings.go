@@ -0,0 +1,180 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// minRecommendedPollingInterval is the polling interval in milliseconds below which a warning is returned.
+// LACP partners exchange LACPDUs every 30 seconds with the slow rate and every second with the fast rate,
+// so polling more often does not detect a failure sooner.
+const minRecommendedPollingInterval = 1000
+
+// warnings returns the admission warnings of a valid monitor. oldMonitor is nil on creation.
+func (v *pflacpmonitorValidator) warnings(ctx context.Context, oldMonitor, monitor *PFLACPMonitor) admission.Warnings {
+	var warnings admission.Warnings
+
+	if len(monitor.Spec.NodeSelector) == 0 && monitor.Spec.NodeLabelSelector == nil {
+		warnings = append(warnings, "spec.nodeSelector is not set: the relay is deployed on every node, including control-plane nodes")
+	}
+
+	if monitor.Spec.PollingInterval != 0 && monitor.Spec.PollingInterval < minRecommendedPollingInterval {
+		warnings = append(warnings, fmt.Sprintf("spec.pollingInterval of %dms is far below the LACP slow-rate period of 30s: "+
+			"polling more often than every %dms adds load without detecting failures sooner", monitor.Spec.PollingInterval, minRecommendedPollingInterval))
+	}
+
+	if oldMonitor != nil {
+		warnings = append(warnings, daemonSetNameWarnings(monitor)...)
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, timeoutList)
+	defer cancel()
+
+	nodeList := &corev1.NodeList{}
+	if err := v.Client.List(ctxTimeout, nodeList); err != nil {
+		pflacpmonitorlog.Error(err, "unable to list nodes, skipping warnings")
+		return warnings
+	}
+
+	groups, err := interfaceGroups(monitor, nodeList)
+	if err != nil {
+		return warnings
+	}
+	warnings = append(warnings, nodeSelectorWarnings(monitor, groups)...)
+
+	nodeStateList := &PFLACPNodeStateList{}
+	if err := v.Client.List(ctxTimeout, nodeStateList); err != nil {
+		pflacpmonitorlog.Error(err, "unable to list PFLACPNodeState, skipping warnings")
+		return warnings
+	}
+
+	inventory := NodeStateInventory(nodeStateList)
+	warnings = append(warnings, missingInterfaceWarnings(groups, inventory)...)
+	if oldMonitor != nil {
+		warnings = append(warnings, failedOverWarnings(oldMonitor, groups, inventory)...)
+	}
+
+	return warnings
+}
+
+// daemonSetNameWarnings warns about the DaemonSet names of an updated monitor that are rejected on creation.
+func daemonSetNameWarnings(monitor *PFLACPMonitor) admission.Warnings {
+	var warnings admission.Warnings
+	for _, err := range validateDaemonSetNames(monitor) {
+		warnings = append(warnings, err.Error())
+	}
+	return warnings
+}
+
+// nodeSelectorWarnings warns about the node selectors of the monitor and its overrides matching no node.
+func nodeSelectorWarnings(monitor *PFLACPMonitor, groups []interfaceGroup) admission.Warnings {
+	var warnings admission.Warnings
+
+	selected := 0
+	for _, group := range groups {
+		selected += group.nodes.Len()
+	}
+	if selected == 0 {
+		return append(warnings, "the node selectors currently match no node")
+	}
+
+	for i, override := range monitor.Spec.NodeOverrides {
+		if groups[i+1].nodes.Len() == 0 {
+			warnings = append(warnings, fmt.Sprintf("spec.nodeOverrides[%s] currently applies to no node", override.Name))
+		}
+	}
+
+	return warnings
+}
+
+// missingInterfaceWarnings warns about the entries of the interface lists that match no interface
+// on the selected nodes discovered by the node agent.
+func missingInterfaceWarnings(groups []interfaceGroup, inventory map[string][]HostInterface) admission.Warnings {
+	var warnings admission.Warnings
+
+	for _, group := range groups {
+		nodes := make([]string, 0, group.nodes.Len())
+		for _, node := range sets.List(group.nodes) {
+			if _, ok := inventory[node]; ok {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		for _, entry := range group.interfaces {
+			found := slices.ContainsFunc(nodes, func(node string) bool {
+				return interfaceFound(entry, inventory[node])
+			})
+			if !found {
+				warnings = append(warnings, fmt.Sprintf("interface %s was not found on any selected node", entry))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// interfaceFound checks whether an entry of an interface list matches any of the interfaces.
+func interfaceFound(entry string, ifaces []HostInterface) bool {
+	selector, err := ParseInterfaceSelector(entry)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(ifaces, func(iface HostInterface) bool {
+		if selector == nil {
+			return iface.Name == entry
+		}
+		return selector.Matches(iface)
+	})
+}
+
+// failedOverWarnings warns about the interfaces reported with LACP down in the status of the monitor
+// that the updated monitor no longer monitors. The relay stops restoring the link state of their VFs.
+func failedOverWarnings(oldMonitor *PFLACPMonitor, groups []interfaceGroup, inventory map[string][]HostInterface) admission.Warnings {
+	var warnings admission.Warnings
+
+	for _, status := range oldMonitor.Status.Interfaces {
+		if status.LACP != LACPStateDown {
+			continue
+		}
+
+		var names []string
+		for _, group := range groups {
+			if group.nodes.Has(status.NodeName) {
+				names, _ = ResolveInterfaces(group.interfaces, inventory[status.NodeName])
+				break
+			}
+		}
+
+		if !slices.Contains(names, status.Name) {
+			warnings = append(warnings, fmt.Sprintf("interface %s is failed over on node %s and would no longer be monitored: "+
+				"the link state of its VFs would not be restored when LACP recovers", status.Name, status.NodeName))
+		}
+	}
+
+	return warnings
+}
@@ -21,17 +21,34 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/pf-status-relay-operator/internal/metrics"
 )
 
 const timeoutList = 60 * time.Second
 
+// DefaultPollingInterval is the polling interval in milliseconds of the monitors that do not set one.
+const DefaultPollingInterval = 1000
+
+// Standard labels set on the monitors by the defaulting webhook, if missing.
+const (
+	LabelName     = "app.kubernetes.io/name"
+	LabelInstance = "app.kubernetes.io/instance"
+	LabelPartOf   = "app.kubernetes.io/part-of"
+
+	appName = "pf-status-relay-operator"
+)
+
 // log is for logging in this package.
 var pflacpmonitorlog = logf.Log.WithName("pflacpmonitor-resource")
 
@@ -41,41 +58,102 @@ type pflacpmonitorValidator struct {
 
 var _ admission.Validator[*PFLACPMonitor] = &pflacpmonitorValidator{}
 
+type pflacpmonitorDefaulter struct{}
+
+var _ admission.Defaulter[*PFLACPMonitor] = &pflacpmonitorDefaulter{}
+
 func (r *PFLACPMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, r).
+		WithDefaulter(&pflacpmonitorDefaulter{}).
 		WithValidator(&pflacpmonitorValidator{Client: mgr.GetClient()}).
 		Complete()
 }
 
+// +kubebuilder:webhook:path=/mutate-pfstatusrelay-openshift-io-v1alpha1-pflacpmonitor,mutating=true,failurePolicy=fail,sideEffects=None,groups=pfstatusrelay.openshift.io,resources=pflacpmonitors,verbs=create;update,versions=v1alpha1,name=mpflacpmonitor.kb.io,admissionReviewVersions=v1
+
+// Default canonicalizes the interface lists of the monitor, so that equivalent entries are compared
+// and passed to the relay the same way, and sets the polling interval and standard labels if missing.
+func (d *pflacpmonitorDefaulter) Default(_ context.Context, obj *PFLACPMonitor) error {
+	pflacpmonitorlog.Info("defaulting", "name", obj.Name, "namespace", obj.Namespace)
+
+	obj.Spec.Interfaces = CanonicalInterfaces(obj.Spec.Interfaces)
+	for i := range obj.Spec.NodeOverrides {
+		obj.Spec.NodeOverrides[i].Interfaces = CanonicalInterfaces(obj.Spec.NodeOverrides[i].Interfaces)
+	}
+
+	if obj.Spec.PollingInterval == 0 {
+		obj.Spec.PollingInterval = DefaultPollingInterval
+	}
+
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	setDefaultLabel(obj.Labels, LabelName, appName)
+	setDefaultLabel(obj.Labels, LabelPartOf, appName)
+	if obj.Name != "" {
+		setDefaultLabel(obj.Labels, LabelInstance, obj.Name)
+	}
+
+	return nil
+}
+
+func setDefaultLabel(labels map[string]string, key, value string) {
+	if _, ok := labels[key]; !ok {
+		labels[key] = value
+	}
+}
+
 // +kubebuilder:webhook:path=/validate-pfstatusrelay-openshift-io-v1alpha1-pflacpmonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=pfstatusrelay.openshift.io,resources=pflacpmonitors,verbs=create;update,versions=v1alpha1,name=vpflacpmonitor.kb.io,admissionReviewVersions=v1
 
 func (v *pflacpmonitorValidator) ValidateCreate(ctx context.Context, obj *PFLACPMonitor) (admission.Warnings, error) {
 	pflacpmonitorlog.Info("validating create", "name", obj.Name, "namespace", obj.Namespace)
-	return v.validate(ctx, obj)
+	return v.validate(ctx, nil, obj)
 }
 
-func (v *pflacpmonitorValidator) ValidateUpdate(ctx context.Context, _, newObj *PFLACPMonitor) (admission.Warnings, error) {
+func (v *pflacpmonitorValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *PFLACPMonitor) (admission.Warnings, error) {
 	pflacpmonitorlog.Info("validating update", "name", newObj.Name, "namespace", newObj.Namespace)
-	return v.validate(ctx, newObj)
+
+	// The finalizer of a monitor losing a conflict must be removable, and a monitor already in conflict must be
+	// able to get its finalizer and status
+	if !newObj.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) {
+		return nil, nil
+	}
+
+	return v.validate(ctx, oldObj, newObj)
 }
 
 func (v *pflacpmonitorValidator) ValidateDelete(_ context.Context, _ *PFLACPMonitor) (admission.Warnings, error) {
 	return nil, nil
 }
 
-func (v *pflacpmonitorValidator) validate(ctx context.Context, monitor *PFLACPMonitor) (admission.Warnings, error) {
+func (v *pflacpmonitorValidator) validate(ctx context.Context, oldMonitor, monitor *PFLACPMonitor) (admission.Warnings, error) {
 	if err := monitor.validateSpec(); err != nil {
-		return nil, err
+		return nil, rejected(err)
+	}
+
+	// The DaemonSet names are only enforced on creation, so that the monitors created before remain editable
+	if oldMonitor == nil {
+		if errs := validateDaemonSetNames(monitor); len(errs) > 0 {
+			return nil, rejected(apierrors.NewInvalid(GroupVersion.WithKind("PFLACPMonitor").GroupKind(), monitor.Name, errs))
+		}
 	}
 
 	if err := v.validateInterfaceUniqueness(ctx, monitor); err != nil {
-		return nil, err
+		return nil, rejected(err)
 	}
 
-	return nil, nil
+	return v.warnings(ctx, oldMonitor, monitor), nil
 }
 
-// ValidateInterfaceUniqueness checks if an interface is used by only one PFLACPMonitor
+// rejected counts the rejection of a monitor by its reason, such as Invalid or Conflict, and returns err.
+func rejected(err error) error {
+	metrics.RecordWebhookRejection(string(apierrors.ReasonForError(err)))
+	return err
+}
+
+// ValidateInterfaceUniqueness checks that the interfaces of the monitor are not used by a PFLACPMonitor taking
+// precedence over it. A monitor taking precedence over the monitors it conflicts with is accepted, and takes over
+// their interfaces once reconciled.
 func (v *pflacpmonitorValidator) validateInterfaceUniqueness(ctx context.Context, monitor *PFLACPMonitor) error {
 	ctxTimeout, cancel := context.WithTimeout(ctx, timeoutList)
 	defer cancel()
@@ -86,7 +164,36 @@ func (v *pflacpmonitorValidator) validateInterfaceUniqueness(ctx context.Context
 		return apierrors.NewInternalError(err)
 	}
 
-	err = InterfaceUniqueness(monitor, monitorList)
+	nodeList := &corev1.NodeList{}
+	err = v.Client.List(ctxTimeout, nodeList)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	err = MonitorConflict(monitor, monitorList, nodeList)
+	if err != nil {
+		return apierrors.NewConflict(schema.GroupResource{Group: GroupVersion.Group, Resource: "pflacpmonitors"}, monitor.Name, err)
+	}
+
+	// Monitors of other namespaces are only visible through the interface claims
+	nodeStateList := &PFLACPNodeStateList{}
+	err = v.Client.List(ctxTimeout, nodeStateList)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	claimList := &PFInterfaceClaimList{}
+	err = v.Client.List(ctxTimeout, claimList)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	claims, err := NodeInterfaceClaims(monitor, nodeList, NodeStateInventory(nodeStateList))
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	err = ClaimConflict(monitor, claims, claimList)
 	if err != nil {
 		return apierrors.NewConflict(schema.GroupResource{Group: GroupVersion.Group, Resource: "pflacpmonitors"}, monitor.Name, err)
 	}
@@ -96,10 +203,24 @@ func (v *pflacpmonitorValidator) validateInterfaceUniqueness(ctx context.Context
 
 func (r *PFLACPMonitor) validateSpec() error {
 	var allErrs field.ErrorList
-	if err := validateInterfaces(r.Spec.Interfaces); err != nil {
+	if err := validateInterfaces(r.Spec.Interfaces, field.NewPath("spec").Child("interfaces")); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	for i, override := range r.Spec.NodeOverrides {
+		fldPath := field.NewPath("spec").Child("nodeOverrides").Index(i)
+		if len(override.NodeSelector) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("nodeSelector"), "node selector cannot be empty"))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabels(override.NodeSelector, fldPath.Child("nodeSelector"))...)
+		if err := validateInterfaces(override.Interfaces, fldPath.Child("interfaces")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(r.Spec.NodeLabelSelector,
+		metav1validation.LabelSelectorValidationOptions{}, field.NewPath("spec").Child("nodeLabelSelector"))...)
+
 	if len(allErrs) > 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("PFLACPMonitor").GroupKind(), r.Name, allErrs)
 	}
@@ -107,13 +228,17 @@ func (r *PFLACPMonitor) validateSpec() error {
 	return nil
 }
 
-func validateInterfaces(interfaces []string) *field.Error {
+func validateInterfaces(interfaces []string, fldPath *field.Path) *field.Error {
 	pfs := make([]string, 0, len(interfaces))
 
 	for i := range interfaces {
 		pf := strings.TrimSpace(interfaces[i])
 		if pf == "" {
-			return field.Invalid(field.NewPath("spec").Child("interfaces").Index(i), interfaces[i], "interface cannot be empty")
+			return field.Invalid(fldPath.Index(i), interfaces[i], "interface cannot be empty")
+		}
+
+		if _, err := ParseInterfaceSelector(pf); err != nil {
+			return field.Invalid(fldPath.Index(i), interfaces[i], err.Error())
 		}
 
 		pfs = append(pfs, pf)
@@ -122,7 +247,7 @@ func validateInterfaces(interfaces []string) *field.Error {
 	checkUnique := make(map[string]struct{})
 	for _, pf := range pfs {
 		if _, ok := checkUnique[pf]; ok {
-			return field.Invalid(field.NewPath("spec").Child("interfaces"), pfs, "interfaces must be unique")
+			return field.Invalid(fldPath, pfs, "interfaces must be unique")
 		}
 		checkUnique[pf] = struct{}{}
 	}
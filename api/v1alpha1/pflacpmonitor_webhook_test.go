@@ -18,12 +18,15 @@ package v1alpha1
 
 import (
 	"context"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -38,11 +41,81 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 		err := AddToScheme(scheme)
 		Expect(err).NotTo(HaveOccurred())
 
-		// Create a fake client for our tests
-		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Create a fake client for our tests, with a node selected by every monitor
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
 		validator = &pflacpmonitorValidator{Client: fakeClient}
 	})
 
+	Describe("Default", func() {
+		var defaulter *pflacpmonitorDefaulter
+
+		BeforeEach(func() {
+			defaulter = &pflacpmonitorDefaulter{}
+		})
+
+		It("should canonicalize and sort the interfaces", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces: []string{" eth1", "eth0 ", "ID=8086:159B"},
+					NodeOverrides: []NodeInterfaceOverride{
+						{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"ens2f0", " ens1f0"}},
+					},
+				},
+			}
+			Expect(defaulter.Default(ctx, monitor)).To(Succeed())
+			Expect(monitor.Spec.Interfaces).To(Equal([]string{"eth0", "eth1", "id=8086:159b"}))
+			Expect(monitor.Spec.NodeOverrides[0].Interfaces).To(Equal([]string{"ens1f0", "ens2f0"}))
+		})
+
+		It("should let the validator reject interfaces equal after trimming", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces: []string{"eth0", " eth0"},
+				},
+			}
+			Expect(defaulter.Default(ctx, monitor)).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("interfaces must be unique"))
+		})
+
+		It("should default the polling interval", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec:       PFLACPMonitorSpec{Interfaces: []string{"eth0"}},
+			}
+			Expect(defaulter.Default(ctx, monitor)).To(Succeed())
+			Expect(monitor.Spec.PollingInterval).To(Equal(DefaultPollingInterval))
+
+			monitor.Spec.PollingInterval = 500
+			Expect(defaulter.Default(ctx, monitor)).To(Succeed())
+			Expect(monitor.Spec.PollingInterval).To(Equal(500))
+		})
+
+		It("should add the standard labels without overriding existing ones", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-monitor",
+					Namespace: "default",
+					Labels:    map[string]string{LabelPartOf: "telco"},
+				},
+				Spec: PFLACPMonitorSpec{Interfaces: []string{"eth0"}},
+			}
+			Expect(defaulter.Default(ctx, monitor)).To(Succeed())
+			Expect(monitor.Labels).To(Equal(map[string]string{
+				LabelName:     "pf-status-relay-operator",
+				LabelInstance: "test-monitor",
+				LabelPartOf:   "telco",
+			}))
+		})
+	})
+
 	Describe("ValidateCreate", func() {
 		Context("with invalid spec", func() {
 			It("should reject an empty interface", func() {
@@ -68,6 +141,126 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("interfaces must be unique"))
 			})
+
+			It("should reject an invalid node label selector", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+						NodeLabelSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "zone", Operator: metav1.LabelSelectorOpIn},
+							},
+						},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.nodeLabelSelector.matchExpressions[0].values"))
+			})
+		})
+
+		Context("with interface selectors", func() {
+			It("should accept selectors alongside interface names", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0", "pci=0000:3b:00.*", "id=8086:159b", "driver=ice", "bond=bond0"},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should reject an unknown selector", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0", "vendor=8086"},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.interfaces[1]"))
+				Expect(err.Error()).To(ContainSubstring(`unknown interface selector "vendor"`))
+			})
+
+			It("should reject a malformed PCI ID", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"id=8086"},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.interfaces[0]"))
+			})
+		})
+
+		Context("with node overrides", func() {
+			It("should reject an override without node selector", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+						NodeOverrides: []NodeInterfaceOverride{
+							{Name: "sku-b", Interfaces: []string{"eth1"}},
+						},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.nodeOverrides[0].nodeSelector"))
+			})
+
+			It("should reject duplicate interfaces within an override", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+						NodeOverrides: []NodeInterfaceOverride{
+							{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"eth1", "eth1"}},
+						},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("spec.nodeOverrides[0].interfaces"))
+			})
+
+			It("should name the override DaemonSets apart from the DaemonSets of other monitors", func() {
+				monitor := &PFLACPMonitor{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+				other := &PFLACPMonitor{ObjectMeta: metav1.ObjectMeta{Name: "a-b", Namespace: "default"}}
+				Expect(OverrideDaemonSetName(monitor, "b")).NotTo(Equal(DaemonSetName(other)))
+				Expect(OverrideDaemonSetName(monitor, strings.Repeat("b", 63))).To(HaveLen(len(DaemonSetName(monitor)) + 3 + NameHashLength))
+			})
+
+			It("should reject a name reserved for the override DaemonSets", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "a-o-" + NameHash("b"), Namespace: "default"},
+					Spec:       PFLACPMonitorSpec{Interfaces: []string{"eth0"}},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("metadata.name"))
+			})
+
+			It("should reject a name too long for the DaemonSets", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("m", 40), Namespace: "default"},
+					Spec:       PFLACPMonitorSpec{Interfaces: []string{"eth0"}},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).NotTo(HaveOccurred())
+
+				monitor.Spec.NodeOverrides = []NodeInterfaceOverride{
+					{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"eth1"}},
+				}
+				_, err = validator.ValidateCreate(ctx, monitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is too long"))
+			})
 		})
 
 		Context("with conflicting existing resources", func() {
@@ -92,7 +285,19 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 				}
 				_, err := validator.ValidateCreate(ctx, newMonitor)
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("interfaces [eth2 eth0] conflict with the ones from PFLACPMonitor existing-monitor"))
+				Expect(err.Error()).To(ContainSubstring("interfaces [eth0 eth2] conflict with the ones from PFLACPMonitor existing-monitor on nodes [worker-0]"))
+			})
+
+			It("should allow a new monitor taking precedence over the existing one", func() {
+				newMonitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "new-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth2", "eth0"},
+						Priority:   10,
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, newMonitor)
+				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("should allow a new monitor with unique interfaces", func() {
@@ -105,6 +310,91 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 				_, err := validator.ValidateCreate(ctx, newMonitor)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("should allow deleting a conflicting monitor", func() {
+				oldMonitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "new-monitor",
+						Namespace:  "default",
+						Finalizers: []string{"pfstatusrelay.openshift.io/interface-claims"},
+					},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+					},
+				}
+				newMonitor := oldMonitor.DeepCopy()
+				newMonitor.Finalizers = nil
+				newMonitor.DeletionTimestamp = ptr.To(metav1.Now())
+
+				_, err := validator.ValidateUpdate(ctx, oldMonitor, newMonitor)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should allow adding the finalizer of a conflicting monitor", func() {
+				oldMonitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "new-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+					},
+				}
+				newMonitor := oldMonitor.DeepCopy()
+				newMonitor.Finalizers = []string{"pfstatusrelay.openshift.io/interface-claims"}
+
+				_, err := validator.ValidateUpdate(ctx, oldMonitor, newMonitor)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("rejecting a spec change keeping the conflict")
+				newMonitor.Spec.Interfaces = []string{"eth0", "eth2"}
+				_, err = validator.ValidateUpdate(ctx, oldMonitor, newMonitor)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("with interfaces claimed from another namespace", func() {
+			BeforeEach(func() {
+				claim := &PFInterfaceClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+					Spec: PFInterfaceClaimSpec{
+						Claims: []InterfaceClaim{{Interface: "eth0", Monitor: "other/existing-monitor"}},
+					},
+				}
+				Expect(validator.Client.Create(ctx, claim)).To(Succeed())
+			})
+
+			It("should reject a new monitor that uses a claimed interface", func() {
+				newMonitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "new-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, newMonitor)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("interface eth0 is already claimed on node worker-0 by PFLACPMonitor other/existing-monitor"))
+			})
+
+			It("should allow a new monitor taking precedence over the claim", func() {
+				newMonitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "new-monitor", Namespace: "default"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+						Priority:   10,
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, newMonitor)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should allow the monitor holding the claim", func() {
+				monitor := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "existing-monitor", Namespace: "other"},
+					Spec: PFLACPMonitorSpec{
+						Interfaces: []string{"eth0", "eth1"},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, monitor)
+				Expect(err).NotTo(HaveOccurred())
+			})
 		})
 
 		Context("with a valid spec and no conflicts", func() {
@@ -121,6 +411,125 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 		})
 	})
 
+	Describe("Warnings", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			nodeState := &PFLACPNodeState{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+				Status: PFLACPNodeStateStatus{
+					Interfaces: []PFStatus{
+						{HostInterface: HostInterface{Name: "eth0", Driver: "ice"}},
+					},
+					LastUpdateTime: &now,
+				},
+			}
+			Expect(validator.Client.Create(ctx, nodeState)).To(Succeed())
+		})
+
+		It("should not warn about a selective spec", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces:        []string{"eth0"},
+					PollingInterval:   1000,
+					NodeLabelSelector: &metav1.LabelSelector{},
+				},
+			}
+			warnings, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should warn about a missing node selector", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec:       PFLACPMonitorSpec{Interfaces: []string{"eth0"}},
+			}
+			warnings, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.nodeSelector is not set")))
+		})
+
+		It("should warn about a low polling interval", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces:        []string{"eth0"},
+					PollingInterval:   100,
+					NodeLabelSelector: &metav1.LabelSelector{},
+				},
+			}
+			warnings, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.pollingInterval of 100ms is far below")))
+		})
+
+		It("should warn about interfaces not found on the selected nodes", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces:        []string{"driver=ice", "eth0", "eth1", "driver=mlx5_core"},
+					NodeLabelSelector: &metav1.LabelSelector{},
+				},
+			}
+			warnings, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"interface eth1 was not found on any selected node",
+				"interface driver=mlx5_core was not found on any selected node",
+			))
+		})
+
+		It("should warn about selectors matching no node", func() {
+			monitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces:   []string{"eth0"},
+					NodeSelector: map[string]string{"key": "value"},
+				},
+			}
+			warnings, err := validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("the node selectors currently match no node"))
+
+			monitor.Spec.NodeSelector = nil
+			monitor.Spec.NodeLabelSelector = &metav1.LabelSelector{}
+			monitor.Spec.NodeOverrides = []NodeInterfaceOverride{
+				{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"eth1"}},
+			}
+			warnings, err = validator.ValidateCreate(ctx, monitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("spec.nodeOverrides[sku-b] currently applies to no node"))
+		})
+
+		It("should warn about an update dropping failed over interfaces", func() {
+			oldMonitor := &PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-monitor", Namespace: "default"},
+				Spec: PFLACPMonitorSpec{
+					Interfaces:        []string{"eth0", "eth1"},
+					NodeLabelSelector: &metav1.LabelSelector{},
+				},
+				Status: PFLACPMonitorStatus{
+					Interfaces: []InterfaceStatus{
+						{NodeName: "worker-0", Name: "eth0", LACP: LACPStateDown},
+						{NodeName: "worker-0", Name: "eth1", LACP: LACPStateUp},
+					},
+				},
+			}
+
+			updatedMonitor := oldMonitor.DeepCopy()
+			updatedMonitor.Spec.Interfaces = []string{"eth1"}
+			warnings, err := validator.ValidateUpdate(ctx, oldMonitor, updatedMonitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("interface eth0 is failed over on node worker-0")))
+
+			updatedMonitor.Spec.Interfaces = []string{"driver=ice"}
+			warnings, err = validator.ValidateUpdate(ctx, oldMonitor, updatedMonitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
 	Describe("ValidateUpdate", func() {
 		var oldMonitor *PFLACPMonitor
 
@@ -152,7 +561,16 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 
 			_, err := validator.ValidateUpdate(ctx, oldMonitor, updatedMonitor)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("interfaces [eth0 eth1] conflict with the ones from PFLACPMonitor conflicting-monitor"))
+			Expect(err.Error()).To(ContainSubstring("interfaces [eth0 eth1] conflict with the ones from PFLACPMonitor conflicting-monitor on nodes [worker-0]"))
+		})
+
+		It("should allow an update raising the priority over the conflicting monitor", func() {
+			updatedMonitor := oldMonitor.DeepCopy()
+			updatedMonitor.Spec.Interfaces = []string{"eth0", "eth1"}
+			updatedMonitor.Spec.Priority = 10
+
+			_, err := validator.ValidateUpdate(ctx, oldMonitor, updatedMonitor)
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should allow an update that does not introduce any conflicts", func() {
@@ -170,6 +588,16 @@ var _ = Describe("PFLACPMonitor Webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should only warn about the DaemonSet names of an existing monitor", func() {
+			oldMonitor.Name = "a-o-" + NameHash("b")
+			updatedMonitor := oldMonitor.DeepCopy()
+			updatedMonitor.Spec.Interfaces = []string{"eth0", "eth2"}
+
+			warnings, err := validator.ValidateUpdate(ctx, oldMonitor, updatedMonitor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("metadata.name")))
+		})
+
 		It("should reject an update that introduces duplicate interfaces", func() {
 			updatedMonitor := oldMonitor.DeepCopy()
 			updatedMonitor.Spec.Interfaces = []string{"eth0", "eth0"}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PFLACPNodeStateStatus defines the observed state of PFLACPNodeState
+type PFLACPNodeStateStatus struct {
+	// Interfaces lists the SR-IOV physical functions found on the node
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Interfaces []PFStatus `json:"interfaces,omitempty"`
+
+	// LastUpdateTime is the last time the reported interfaces changed
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// PFStatus describes a physical function found on a node
+type PFStatus struct {
+	HostInterface `json:",inline"`
+
+	// TotalVFs is the number of VFs supported by the PF
+	// +optional
+	TotalVFs int32 `json:"totalVFs,omitempty"`
+
+	// NumVFs is the number of VFs enabled on the PF
+	// +optional
+	NumVFs int32 `json:"numVFs,omitempty"`
+
+	// BondMode is the mode of the bond the PF is enslaved to
+	// +optional
+	BondMode string `json:"bondMode,omitempty"`
+
+	// LACP is the LACP state of the PF. It is only set when the PF is enslaved to an 802.3ad bond
+	// +optional
+	LACP *LACPStatus `json:"lacp,omitempty"`
+
+	// VFLinkState is the link state of the VFs of the PF: auto, enable or disable, or mixed when they differ
+	// +kubebuilder:validation:Enum=auto;enable;disable;mixed;unknown
+	// +optional
+	VFLinkState string `json:"vfLinkState,omitempty"`
+
+	// MonitoredBy is the PFLACPMonitor monitoring the PF, as namespace/name
+	// +optional
+	MonitoredBy string `json:"monitoredBy,omitempty"`
+}
+
+// LACPStatus describes the LACP state of a bond slave
+type LACPStatus struct {
+	// AggregatorID is the ID of the aggregator the PF is attached to
+	// +optional
+	AggregatorID int32 `json:"aggregatorID,omitempty"`
+
+	// PartnerSystem is the MAC address of the LACP partner of the bond
+	// +optional
+	PartnerSystem string `json:"partnerSystem,omitempty"`
+
+	// ActorOperPortState is the operational port state of the PF, as defined by IEEE 802.1AX
+	// +optional
+	ActorOperPortState int32 `json:"actorOperPortState,omitempty"`
+
+	// PartnerOperPortState is the operational port state of the LACP partner, as defined by IEEE 802.1AX
+	// +optional
+	PartnerOperPortState int32 `json:"partnerOperPortState,omitempty"`
+
+	// Synchronized is true when both the PF and its partner are in sync, collecting and distributing
+	// +optional
+	Synchronized bool `json:"synchronized,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// PFLACPNodeState is the Schema for the pflacpnodestates API.
+// It is named after its node and reports the PFs found on it. It is read-only, and maintained by the node agent
+type PFLACPNodeState struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status PFLACPNodeStateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PFLACPNodeStateList contains a list of PFLACPNodeState
+type PFLACPNodeStateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PFLACPNodeState `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PFLACPNodeState{}, &PFLACPNodeStateList{})
+}
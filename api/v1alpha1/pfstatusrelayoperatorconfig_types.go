@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the PFStatusRelayOperatorConfig singleton read by the operator.
+const OperatorConfigName = "cluster"
+
+// Log levels of the operator and the relay.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Log formats of the operator.
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// PFStatusRelayOperatorConfigSpec defines the configuration of the operator
+type PFStatusRelayOperatorConfigSpec struct {
+	// +kubebuilder:validation:Pattern=`^[^@\s]+@sha256:[0-9a-f]{64}$`
+
+	// Image of pf-status-relay, pinned by digest. Defaults to the PF_STATUS_RELAY_IMAGE
+	// environment variable of the operator
+	// +optional
+	RelayImage string `json:"relayImage,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^[^@\s]+@sha256:[0-9a-f]{64}$`
+
+	// Image of the node agent, pinned by digest. Defaults to the PF_STATUS_RELAY_NODE_AGENT_IMAGE
+	// environment variable of the operator, which is set to the image of the operator
+	// +optional
+	NodeAgentImage string `json:"nodeAgentImage,omitempty"`
+
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+
+	// Pull policy of the relay and node agent images
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Secrets of the namespace of each pod used to pull the relay and node agent images
+	// +listType=atomic
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Default settings of the relay pods of all the monitors. A field set in the relayPod of a
+	// monitor takes precedence, and the labels and annotations are merged
+	// +optional
+	RelayPodDefaults *RelayPodTemplate `json:"relayPodDefaults,omitempty"`
+
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+
+	// Log level of the operator and the node agent. Defaults to the --log-level flag of the operator
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// +kubebuilder:validation:Enum=json;text
+
+	// Log format of the operator and the node agent. Defaults to the --log-format flag of the operator
+	// +optional
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// TLS profile of the webhook and metrics servers of the operator on clusters without the
+	// config.openshift.io API. On OpenShift, the profile of the APIServer named cluster is used instead.
+	// Defaults to the --tls-profile flag of the operator
+	// +optional
+	TLSSecurityProfile *configv1.TLSSecurityProfile `json:"tlsSecurityProfile,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the operator config must be named cluster"
+
+// PFStatusRelayOperatorConfig is the Schema for the pfstatusrelayoperatorconfigs API.
+// It holds the configuration shared by all the monitors. The operator only reads the one named cluster,
+// and rolls out its changes to all the relay DaemonSets
+type PFStatusRelayOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PFStatusRelayOperatorConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PFStatusRelayOperatorConfigList contains a list of PFStatusRelayOperatorConfig
+type PFStatusRelayOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PFStatusRelayOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PFStatusRelayOperatorConfig{}, &PFStatusRelayOperatorConfigList{})
+}
@@ -2,23 +2,140 @@ package v1alpha1
 
 import (
 	"fmt"
-	"reflect"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
-// InterfaceUniqueness validates that interfaces do not overlap for daemon sets that share nodes.
-func InterfaceUniqueness(pfMonitor *PFLACPMonitor, pfMonitorList *PFLACPMonitorList) error {
-	for _, monitor := range pfMonitorList.Items {
-		if pfMonitor.Name == monitor.Name {
-			continue
+// InterfaceConflictError is returned when a monitor monitors interfaces of another monitor on the same nodes.
+// +kubebuilder:object:generate=false
+type InterfaceConflictError struct {
+	// Interfaces are the interfaces of the monitor conflicting with the other monitor
+	Interfaces []string
+	// Name and Namespace identify the other monitor
+	Name      string
+	Namespace string
+	// Nodes are the nodes selected by both monitors
+	Nodes []string
+}
+
+func (e *InterfaceConflictError) Error() string {
+	return fmt.Sprintf("interfaces %s conflict with the ones from PFLACPMonitor %s on nodes %s", e.Interfaces, e.Name, e.Nodes)
+}
+
+// MonitorConflict resolves the conflicts between the monitors of pfMonitorList deterministically, whatever the
+// order they are reconciled in. A monitor loses to the monitors taking precedence over it, as defined by
+// MonitorPrecedes, unless they lose themselves. It returns an *InterfaceConflictError naming the monitor
+// pfMonitor loses to, if any.
+func MonitorConflict(pfMonitor *PFLACPMonitor, pfMonitorList *PFLACPMonitorList, nodeList *corev1.NodeList) error {
+	groups, err := interfaceGroups(pfMonitor, nodeList)
+	if err != nil {
+		return err
+	}
+
+	key := MonitorKey(pfMonitor)
+	monitors := make([]*PFLACPMonitor, 0, len(pfMonitorList.Items))
+	for i := range pfMonitorList.Items {
+		monitor := &pfMonitorList.Items[i]
+		if MonitorKey(monitor) != key && MonitorPrecedes(monitor, pfMonitor) {
+			monitors = append(monitors, monitor)
+		}
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		return MonitorPrecedes(monitors[i], monitors[j])
+	})
+
+	// The monitors taking precedence over pfMonitor that do not lose to another one
+	type activeMonitor struct {
+		monitor *PFLACPMonitor
+		groups  []interfaceGroup
+	}
+	var active []activeMonitor
+
+	for _, monitor := range monitors {
+		monitorGroups, err := interfaceGroups(monitor, nodeList)
+		if err != nil {
+			return err
 		}
 
-		if monitor.Status.Degraded {
-			continue
+		lost := slices.ContainsFunc(active, func(winner activeMonitor) bool {
+			return groupsConflict(monitorGroups, winner.monitor, winner.groups) != nil
+		})
+		if !lost {
+			active = append(active, activeMonitor{monitor: monitor, groups: monitorGroups})
 		}
+	}
+
+	for _, winner := range active {
+		if err := groupsConflict(groups, winner.monitor, winner.groups); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MonitorPrecedes checks whether monitor a takes precedence over monitor b for the interfaces they both monitor:
+// the monitor with the highest spec.priority wins, then the oldest one, then the first one by name and namespace.
+func MonitorPrecedes(a, b *PFLACPMonitor) bool {
+	return rankOf(a).precedes(rankOf(b))
+}
+
+// monitorRank holds the fields of a monitor deciding its precedence.
+// +kubebuilder:object:generate=false
+type monitorRank struct {
+	priority  int32
+	created   metav1.Time
+	name      string
+	namespace string
+}
+
+func rankOf(pfMonitor *PFLACPMonitor) monitorRank {
+	return monitorRank{
+		priority:  pfMonitor.Spec.Priority,
+		created:   pfMonitor.CreationTimestamp,
+		name:      pfMonitor.Name,
+		namespace: pfMonitor.Namespace,
+	}
+}
+
+func (r monitorRank) precedes(o monitorRank) bool {
+	if r.priority != o.priority {
+		return r.priority > o.priority
+	}
+	if !r.created.Equal(&o.created) {
+		// Monitors being created have no creation timestamp yet, and are the newest
+		if r.created.IsZero() || o.created.IsZero() {
+			return o.created.IsZero()
+		}
+		return r.created.Before(&o.created)
+	}
+	if r.name != o.name {
+		return r.name < o.name
+	}
+	return r.namespace < o.namespace
+}
+
+// groupsConflict returns an *InterfaceConflictError if the interface groups of a monitor share interfaces
+// on the same nodes with the groups of another monitor.
+func groupsConflict(groups []interfaceGroup, monitor *PFLACPMonitor, monitorGroups []interfaceGroup) error {
+	for _, group := range groups {
+		for _, monitorGroup := range monitorGroups {
+			if areInterfacesUnique(group.interfaces, monitorGroup.interfaces) {
+				continue
+			}
 
-		if monitor.Spec.NodeSelector == nil || pfMonitor.Spec.NodeSelector == nil || nodeSelectorOverlaps(pfMonitor.Spec.NodeSelector, monitor.Spec.NodeSelector) {
-			if !areInterfacesUnique(pfMonitor.Spec.Interfaces, monitor.Spec.Interfaces) {
-				return fmt.Errorf("interfaces %s conflict with the ones from PFLACPMonitor %s", pfMonitor.Spec.Interfaces, monitor.Name)
+			if sharedNodes := group.nodes.Intersection(monitorGroup.nodes); sharedNodes.Len() > 0 {
+				return &InterfaceConflictError{
+					Interfaces: group.interfaces,
+					Name:       monitor.Name,
+					Namespace:  monitor.Namespace,
+					Nodes:      sets.List(sharedNodes),
+				}
 			}
 		}
 	}
@@ -26,14 +143,99 @@ func InterfaceUniqueness(pfMonitor *PFLACPMonitor, pfMonitorList *PFLACPMonitorL
 	return nil
 }
 
-// nodeSelectorOverlaps checks if two node selectors overlap.
-func nodeSelectorOverlaps(nodeSelector1, nodeSelector2 map[string]string) bool {
-	for key, value := range nodeSelector1 {
-		if v, ok := nodeSelector2[key]; ok && reflect.DeepEqual(value, v) {
-			return true
+// interfaceGroup is a set of nodes of a monitor that monitor the same interfaces.
+// +kubebuilder:object:generate=false
+type interfaceGroup struct {
+	interfaces []string
+	nodes      sets.Set[string]
+}
+
+// interfaceGroups splits the nodes of nodeList selected by the monitor by the canonical interfaces they monitor,
+// so that monitors created before the defaulting webhook are compared the same way. The first group holds the nodes not matching any override, followed by one group per override.
+func interfaceGroups(pfMonitor *PFLACPMonitor, nodeList *corev1.NodeList) ([]interfaceGroup, error) {
+	nodes, err := SelectedNodes(pfMonitor, nodeList)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]interfaceGroup, 0, len(pfMonitor.Spec.NodeOverrides)+1)
+	groups = append(groups, interfaceGroup{interfaces: CanonicalInterfaces(pfMonitor.Spec.Interfaces), nodes: sets.New[string]()})
+	for _, override := range pfMonitor.Spec.NodeOverrides {
+		groups = append(groups, interfaceGroup{interfaces: CanonicalInterfaces(override.Interfaces), nodes: sets.New[string]()})
+	}
+
+	for _, node := range nodeList.Items {
+		if !nodes.Has(node.Name) {
+			continue
+		}
+
+		groups[nodeOverrideIndex(pfMonitor, node.Labels)+1].nodes.Insert(node.Name)
+	}
+
+	return groups, nil
+}
+
+// nodeOverrideIndex returns the index of the first override of the monitor matching the node labels, or -1.
+func nodeOverrideIndex(pfMonitor *PFLACPMonitor, nodeLabels map[string]string) int {
+	for i, override := range pfMonitor.Spec.NodeOverrides {
+		if labels.SelectorFromSet(override.NodeSelector).Matches(labels.Set(nodeLabels)) {
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// NodeInterfaces returns the canonical interfaces the monitor monitors on the node, along with the name of the
+// override they come from, which is empty for spec.interfaces. It returns nil if the node is not selected.
+func NodeInterfaces(pfMonitor *PFLACPMonitor, node *corev1.Node) ([]string, string, error) {
+	selector, err := NodeSelector(pfMonitor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !selector.Matches(labels.Set(node.Labels)) {
+		return nil, "", nil
+	}
+
+	if i := nodeOverrideIndex(pfMonitor, node.Labels); i >= 0 {
+		override := pfMonitor.Spec.NodeOverrides[i]
+		return CanonicalInterfaces(override.Interfaces), override.Name, nil
+	}
+
+	return CanonicalInterfaces(pfMonitor.Spec.Interfaces), "", nil
+}
+
+// NodeSelector returns the label selector combining the nodeSelector and nodeLabelSelector fields of the monitor.
+func NodeSelector(pfMonitor *PFLACPMonitor) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(pfMonitor.Spec.NodeSelector)
+
+	if pfMonitor.Spec.NodeLabelSelector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(pfMonitor.Spec.NodeLabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node label selector of PFLACPMonitor %s: %w", pfMonitor.Name, err)
+		}
+		requirements, _ := labelSelector.Requirements()
+		selector = selector.Add(requirements...)
+	}
+
+	return selector, nil
+}
+
+// SelectedNodes returns the names of the nodes of nodeList selected by the monitor.
+func SelectedNodes(pfMonitor *PFLACPMonitor, nodeList *corev1.NodeList) (sets.Set[string], error) {
+	selector, err := NodeSelector(pfMonitor)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := sets.New[string]()
+	for _, node := range nodeList.Items {
+		if selector.Matches(labels.Set(node.Labels)) {
+			nodes.Insert(node.Name)
+		}
+	}
+
+	return nodes, nil
 }
 
 // areInterfacesUnique checks if two string slices contain any common elements.
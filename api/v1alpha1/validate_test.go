@@ -1,16 +1,30 @@
 package v1alpha1
 
 import (
+	"errors"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func newNode(name string, nodeLabels map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: nodeLabels,
+		},
+	}
+}
+
 var _ = Describe("Validator", func() {
 	Describe("NodeSelector", func() {
 		var (
 			pfMonitor1, pfMonitor2 *PFLACPMonitor
 			pfMonitorList          *PFLACPMonitorList
+			nodeList               *corev1.NodeList
 		)
 
 		BeforeEach(func() {
@@ -25,6 +39,13 @@ var _ = Describe("Validator", func() {
 					Name: "monitor2",
 				},
 			}
+
+			nodeList = &corev1.NodeList{
+				Items: []corev1.Node{
+					newNode("node-a", map[string]string{"key1": "value1"}),
+					newNode("node-b", map[string]string{"key2": "value2"}),
+				},
+			}
 		})
 
 		It("should pass if interfaces are equal but node selectors different", func() {
@@ -38,11 +59,13 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should return error if interfaces are equal and node selector overlaps", func() {
+			nodeList.Items = append(nodeList.Items, newNode("node-c", map[string]string{"key1": "value1", "key2": "value2"}))
+
 			pfMonitor1.Spec.NodeSelector = map[string]string{"key1": "value1", "key2": "value2"}
 			pfMonitor1.Spec.Interfaces = []string{"eth0", "eth3"}
 
@@ -53,7 +76,7 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -68,7 +91,7 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -83,7 +106,7 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -98,7 +121,7 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -113,8 +136,313 @@ var _ = Describe("Validator", func() {
 				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
 			}
 
-			err := InterfaceUniqueness(pfMonitor1, pfMonitorList)
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should pass if node selectors share labels but select different nodes", func() {
+			nodeList.Items = []corev1.Node{
+				newNode("node-a", map[string]string{"role": "worker", "host": "a"}),
+				newNode("node-b", map[string]string{"role": "worker", "host": "b"}),
+			}
+
+			pfMonitor1.Spec.NodeSelector = map[string]string{"role": "worker", "host": "a"}
+			pfMonitor1.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitor2.Spec.NodeSelector = map[string]string{"role": "worker", "host": "b"}
+			pfMonitor2.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitorList = &PFLACPMonitorList{
+				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+			}
+
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return error listing the shared nodes if disjoint labels select the same node", func() {
+			nodeList.Items = append(nodeList.Items, newNode("node-c", map[string]string{"key1": "value1", "key2": "value2"}))
+
+			pfMonitor1.Spec.NodeSelector = map[string]string{"key1": "value1"}
+			pfMonitor1.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitor2.Spec.NodeSelector = map[string]string{"key2": "value2"}
+			pfMonitor2.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitorList = &PFLACPMonitorList{
+				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+			}
+
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+			Expect(err).To(MatchError("interfaces [eth0] conflict with the ones from PFLACPMonitor monitor1 on nodes [node-c]"))
+		})
+
+		It("should pass if a node selector matches no node", func() {
+			pfMonitor1.Spec.NodeSelector = map[string]string{"key3": "value3"}
+			pfMonitor1.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitor2.Spec.NodeSelector = nil
+			pfMonitor2.Spec.Interfaces = []string{"eth0"}
+
+			pfMonitorList = &PFLACPMonitorList{
+				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+			}
+
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should compare the canonical interfaces", func() {
+			pfMonitor1.Spec.Interfaces = []string{"pci=0000:3B:00.*"}
+			pfMonitor2.Spec.Interfaces = []string{" PCI=0000:3b:00.* "}
+			pfMonitorList = &PFLACPMonitorList{
+				Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+			}
+
+			err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+			Expect(err).To(MatchError(ContainSubstring("interfaces [pci=0000:3b:00.*] conflict")))
+		})
+
+		Context("with precedence", func() {
+			var created metav1.Time
+
+			BeforeEach(func() {
+				created = metav1.NewTime(time.Date(2024, 8, 9, 21, 48, 3, 0, time.UTC))
+				pfMonitor1.Spec.Interfaces = []string{"eth0"}
+				pfMonitor2.Spec.Interfaces = []string{"eth0"}
+			})
+
+			DescribeTable("MonitorPrecedes",
+				func(priority1, priority2 int32, age1, age2 time.Duration, expected bool) {
+					pfMonitor1.Spec.Priority = priority1
+					pfMonitor2.Spec.Priority = priority2
+					pfMonitor1.CreationTimestamp = metav1.NewTime(created.Add(-age1))
+					pfMonitor2.CreationTimestamp = metav1.NewTime(created.Add(-age2))
+					Expect(MonitorPrecedes(pfMonitor1, pfMonitor2)).To(Equal(expected))
+					Expect(MonitorPrecedes(pfMonitor2, pfMonitor1)).To(Equal(!expected))
+				},
+				Entry("higher priority", int32(1), int32(0), time.Duration(0), time.Hour, true),
+				Entry("lower priority", int32(-1), int32(0), time.Hour, time.Duration(0), false),
+				Entry("older", int32(0), int32(0), time.Hour, time.Duration(0), true),
+				Entry("newer", int32(0), int32(0), time.Duration(0), time.Hour, false),
+				Entry("same age, first by name", int32(0), int32(0), time.Hour, time.Hour, true),
+			)
+
+			It("should rank monitors being created last", func() {
+				pfMonitor2.CreationTimestamp = created
+				Expect(MonitorPrecedes(pfMonitor2, pfMonitor1)).To(BeTrue())
+			})
+
+			It("should let the monitor taking precedence win, whatever its status", func() {
+				pfMonitor1.CreationTimestamp = created
+				pfMonitor2.CreationTimestamp = metav1.NewTime(created.Add(time.Hour))
+				pfMonitor1.Status.Conditions = []metav1.Condition{
+					{Type: ConditionInterfaceConflict, Status: metav1.ConditionTrue, Reason: ReasonInterfacesInUse},
+				}
+				pfMonitorList = &PFLACPMonitorList{Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2}}
+
+				Expect(MonitorConflict(pfMonitor1, pfMonitorList, nodeList)).To(Succeed())
+
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				var conflict *InterfaceConflictError
+				Expect(errors.As(err, &conflict)).To(BeTrue())
+				Expect(conflict.Name).To(Equal("monitor1"))
+			})
+
+			It("should ignore monitors losing to another monitor", func() {
+				pfMonitor0 := &PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: "monitor0"},
+					Spec:       PFLACPMonitorSpec{Interfaces: []string{"eth0"}, NodeSelector: map[string]string{"key1": "value1"}, Priority: 10},
+				}
+				pfMonitor1.Spec.Priority = 5
+				pfMonitor2.Spec.NodeSelector = map[string]string{"key2": "value2"}
+				pfMonitorList = &PFLACPMonitorList{Items: []PFLACPMonitor{*pfMonitor0, *pfMonitor1, *pfMonitor2}}
+
+				Expect(MonitorConflict(pfMonitor1, pfMonitorList, nodeList)).To(MatchError(ContainSubstring("PFLACPMonitor monitor0")))
+				Expect(MonitorConflict(pfMonitor2, pfMonitorList, nodeList)).To(Succeed())
+			})
+		})
+
+		Context("with node label selectors", func() {
+			BeforeEach(func() {
+				pfMonitor1.Spec.Interfaces = []string{"eth0"}
+				pfMonitor2.Spec.Interfaces = []string{"eth0"}
+
+				nodeList.Items = append(nodeList.Items,
+					newNode("node-zone-a", map[string]string{"zone": "a", "node-role.kubernetes.io/worker": ""}),
+					newNode("node-zone-b", map[string]string{"zone": "b", "sriov-lacp": "disabled"}),
+					newNode("node-zone-c", map[string]string{"zone": "c", "node-role.kubernetes.io/worker": "", "sriov-lacp": "disabled"}),
+				)
+			})
+
+			It("should pass if the label selectors are disjoint", func() {
+				pfMonitor1.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+					},
+				}
+				pfMonitor2.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"c"}},
+					},
+				}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should return error if the label selectors overlap", func() {
+				pfMonitor1.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+					},
+				}
+				pfMonitor2.Spec.NodeSelector = map[string]string{"zone": "b"}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should pass if a NotIn expression excludes the nodes of the other monitor", func() {
+				pfMonitor1.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/worker": ""}
+				pfMonitor1.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+					},
+				}
+				pfMonitor2.Spec.NodeSelector = map[string]string{"sriov-lacp": "disabled"}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should pass if Exists and DoesNotExist expressions exclude each other", func() {
+				pfMonitor1.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpExists},
+					},
+				}
+				pfMonitor2.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should return error if the other monitor selects every node", func() {
+				pfMonitor1.Spec.NodeLabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+					},
+				}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("with node overrides", func() {
+			BeforeEach(func() {
+				nodeList.Items = []corev1.Node{
+					newNode("node-sku-a", map[string]string{"sku": "a"}),
+					newNode("node-sku-b", map[string]string{"sku": "b"}),
+				}
+			})
+
+			It("should pass if the interfaces of each node group are different", func() {
+				pfMonitor1.Spec.Interfaces = []string{"ens1f0"}
+				pfMonitor1.Spec.NodeOverrides = []NodeInterfaceOverride{
+					{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"enp94s0f0"}},
+				}
+				pfMonitor2.Spec.NodeSelector = map[string]string{"sku": "b"}
+				pfMonitor2.Spec.Interfaces = []string{"ens1f0"}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should return error if the interfaces of an override conflict", func() {
+				pfMonitor1.Spec.Interfaces = []string{"ens1f0"}
+				pfMonitor1.Spec.NodeOverrides = []NodeInterfaceOverride{
+					{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"enp94s0f0"}},
+				}
+				pfMonitor2.Spec.Interfaces = []string{"enp94s0f0"}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).To(MatchError("interfaces [enp94s0f0] conflict with the ones from PFLACPMonitor monitor1 on nodes [node-sku-b]"))
+			})
+
+			It("should apply the first matching override only", func() {
+				pfMonitor1.Spec.Interfaces = []string{"ens1f0"}
+				pfMonitor1.Spec.NodeOverrides = []NodeInterfaceOverride{
+					{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"enp94s0f0"}},
+					{Name: "sku-b-legacy", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"eth0"}},
+				}
+				pfMonitor2.Spec.Interfaces = []string{"eth0"}
+				pfMonitorList = &PFLACPMonitorList{
+					Items: []PFLACPMonitor{*pfMonitor1, *pfMonitor2},
+				}
+				err := MonitorConflict(pfMonitor2, pfMonitorList, nodeList)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("NodeInterfaces", func() {
+			BeforeEach(func() {
+				pfMonitor1.Spec.Interfaces = []string{"ens1f0"}
+				pfMonitor1.Spec.NodeSelector = map[string]string{"role": "worker"}
+				pfMonitor1.Spec.NodeOverrides = []NodeInterfaceOverride{
+					{Name: "sku-b", NodeSelector: map[string]string{"sku": "b"}, Interfaces: []string{"enp94s0f0"}},
+				}
+			})
+
+			It("should return the interfaces of the matching override", func() {
+				node := newNode("node-sku-b", map[string]string{"role": "worker", "sku": "b"})
+				interfaces, override, err := NodeInterfaces(pfMonitor1, &node)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(interfaces).To(Equal([]string{"enp94s0f0"}))
+				Expect(override).To(Equal("sku-b"))
+			})
+
+			It("should return spec.interfaces when no override matches", func() {
+				node := newNode("node-sku-a", map[string]string{"role": "worker", "sku": "a"})
+				interfaces, override, err := NodeInterfaces(pfMonitor1, &node)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(interfaces).To(Equal([]string{"ens1f0"}))
+				Expect(override).To(BeEmpty())
+			})
+
+			It("should return the canonical interfaces", func() {
+				pfMonitor1.Spec.Interfaces = []string{"eth1 ", "DRIVER=ice"}
+				node := newNode("node-sku-a", map[string]string{"role": "worker", "sku": "a"})
+				interfaces, _, err := NodeInterfaces(pfMonitor1, &node)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(interfaces).To(Equal([]string{"driver=ice", "eth1"}))
+			})
+
+			It("should return nil for nodes not selected", func() {
+				node := newNode("master-0", map[string]string{"sku": "b"})
+				interfaces, _, err := NodeInterfaces(pfMonitor1, &node)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(interfaces).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,345 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &PFLACPMonitor{}
+
+// NodeSelectorsAnnotation holds the nodeSelector and nodeLabelSelector of a v1alpha1 monitor, which v1beta1
+// merges into its nodeSelector, so that they are restored when the monitor is converted back unchanged.
+const NodeSelectorsAnnotation = "pfstatusrelay.openshift.io/v1alpha1-node-selectors"
+
+// hubNodeSelectors are the node selectors of a v1alpha1 monitor kept in NodeSelectorsAnnotation.
+// +kubebuilder:object:generate=false
+type hubNodeSelectors struct {
+	NodeSelector      map[string]string     `json:"nodeSelector,omitempty"`
+	NodeLabelSelector *metav1.LabelSelector `json:"nodeLabelSelector,omitempty"`
+}
+
+// ConvertTo converts this PFLACPMonitor to the Hub version (v1alpha1).
+func (src *PFLACPMonitor) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.PFLACPMonitor)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec = v1alpha1.PFLACPMonitorSpec{
+		Interfaces: interfacesToHub(src.Spec.Interfaces),
+		// The node selector of v1beta1 supports set-based requirements, so it is kept as a whole
+		// in the nodeLabelSelector of v1alpha1
+		NodeLabelSelector: src.Spec.NodeSelector.DeepCopy(),
+		Priority:          src.Spec.Priority,
+		RelayPod:          (*v1alpha1.RelayPodTemplate)(src.Spec.RelayPod.DeepCopy()),
+		Rollout:           rolloutToHub(src.Spec.Rollout),
+		LogLevel:          src.Spec.LogLevel,
+		LogLevelOverride:  (*v1alpha1.LogLevelOverride)(src.Spec.LogLevelOverride.DeepCopy()),
+	}
+	if src.Spec.PollingInterval != nil {
+		dst.Spec.PollingInterval = int(src.Spec.PollingInterval.Milliseconds())
+	}
+	restoreNodeSelectors(dst, src.Spec.NodeSelector)
+	for _, override := range src.Spec.NodeOverrides {
+		dst.Spec.NodeOverrides = append(dst.Spec.NodeOverrides, v1alpha1.NodeInterfaceOverride{
+			Name:         override.Name,
+			NodeSelector: copyLabels(override.NodeSelector),
+			Interfaces:   interfacesToHub(override.Interfaces),
+		})
+	}
+
+	dst.Status = v1alpha1.PFLACPMonitorStatus{
+		ObservedGeneration:     src.Status.ObservedGeneration,
+		Conditions:             copyConditions(src.Status.Conditions),
+		DesiredNumberScheduled: src.Status.DesiredNumberScheduled,
+		NumberReady:            src.Status.NumberReady,
+		UpdatedNumberScheduled: src.Status.UpdatedNumberScheduled,
+		NumberUnavailable:      src.Status.NumberUnavailable,
+		WinningMonitor:         src.Status.WinningMonitor,
+		Rollout:                (*v1alpha1.RolloutStatus)(src.Status.Rollout.DeepCopy()),
+	}
+	for _, node := range src.Status.UnreadyNodes {
+		dst.Status.UnreadyNodes = append(dst.Status.UnreadyNodes, v1alpha1.NodeRelayStatus(node))
+	}
+	for _, iface := range src.Status.Interfaces {
+		dst.Status.Interfaces = append(dst.Status.Interfaces, v1alpha1.InterfaceStatus(iface))
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *PFLACPMonitor) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.PFLACPMonitor)
+	if !ok {
+		return fmt.Errorf("unexpected hub type %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec = PFLACPMonitorSpec{
+		Interfaces:       interfacesFromHub(src.Spec.Interfaces),
+		NodeSelector:     mergeNodeSelectors(src.Spec.NodeSelector, src.Spec.NodeLabelSelector),
+		Priority:         src.Spec.Priority,
+		RelayPod:         (*RelayPodTemplate)(src.Spec.RelayPod.DeepCopy()),
+		Rollout:          rolloutFromHub(src.Spec.Rollout),
+		LogLevel:         src.Spec.LogLevel,
+		LogLevelOverride: (*LogLevelOverride)(src.Spec.LogLevelOverride.DeepCopy()),
+	}
+	if src.Spec.PollingInterval != 0 {
+		dst.Spec.PollingInterval = &metav1.Duration{Duration: time.Duration(src.Spec.PollingInterval) * time.Millisecond}
+	}
+	if err := keepNodeSelectors(dst, src); err != nil {
+		return err
+	}
+	for _, override := range src.Spec.NodeOverrides {
+		dst.Spec.NodeOverrides = append(dst.Spec.NodeOverrides, NodeInterfaceOverride{
+			Name:         override.Name,
+			NodeSelector: copyLabels(override.NodeSelector),
+			Interfaces:   interfacesFromHub(override.Interfaces),
+		})
+	}
+
+	dst.Status = PFLACPMonitorStatus{
+		ObservedGeneration:     src.Status.ObservedGeneration,
+		Conditions:             copyConditions(src.Status.Conditions),
+		DesiredNumberScheduled: src.Status.DesiredNumberScheduled,
+		NumberReady:            src.Status.NumberReady,
+		UpdatedNumberScheduled: src.Status.UpdatedNumberScheduled,
+		NumberUnavailable:      src.Status.NumberUnavailable,
+		WinningMonitor:         src.Status.WinningMonitor,
+		Rollout:                (*RolloutStatus)(src.Status.Rollout.DeepCopy()),
+	}
+	for _, node := range src.Status.UnreadyNodes {
+		dst.Status.UnreadyNodes = append(dst.Status.UnreadyNodes, NodeRelayStatus(node))
+	}
+	for _, iface := range src.Status.Interfaces {
+		dst.Status.Interfaces = append(dst.Status.Interfaces, InterfaceStatus(iface))
+	}
+
+	return nil
+}
+
+// keepNodeSelectors records the node selectors of the v1alpha1 monitor in NodeSelectorsAnnotation when they are
+// merged into a single selector, that is when its nodeSelector is set.
+func keepNodeSelectors(dst *PFLACPMonitor, src *v1alpha1.PFLACPMonitor) error {
+	delete(dst.Annotations, NodeSelectorsAnnotation)
+	if len(src.Spec.NodeSelector) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(hubNodeSelectors{
+		NodeSelector:      src.Spec.NodeSelector,
+		NodeLabelSelector: src.Spec.NodeLabelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the node selectors: %w", err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = make(map[string]string, 1)
+	}
+	dst.Annotations[NodeSelectorsAnnotation] = string(data)
+	return nil
+}
+
+// restoreNodeSelectors restores the node selectors of the v1alpha1 monitor recorded in NodeSelectorsAnnotation,
+// unless nodeSelector, the selector of the v1beta1 monitor, changed since. The annotation is removed in any case.
+func restoreNodeSelectors(dst *v1alpha1.PFLACPMonitor, nodeSelector *metav1.LabelSelector) {
+	data, found := dst.Annotations[NodeSelectorsAnnotation]
+	if !found {
+		return
+	}
+	delete(dst.Annotations, NodeSelectorsAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	// A malformed annotation is dropped, the merged selector selecting the same nodes
+	var selectors hubNodeSelectors
+	if err := json.Unmarshal([]byte(data), &selectors); err != nil {
+		return
+	}
+	if !equality.Semantic.DeepEqual(mergeNodeSelectors(selectors.NodeSelector, selectors.NodeLabelSelector), nodeSelector) {
+		return
+	}
+
+	dst.Spec.NodeSelector = selectors.NodeSelector
+	dst.Spec.NodeLabelSelector = selectors.NodeLabelSelector
+}
+
+// rolloutToHub converts a rollout strategy to v1alpha1.
+func rolloutToHub(rollout *RolloutStrategy) *v1alpha1.RolloutStrategy {
+	if rollout == nil {
+		return nil
+	}
+
+	rollout = rollout.DeepCopy()
+	return &v1alpha1.RolloutStrategy{
+		Type:           rollout.Type,
+		MaxUnavailable: rollout.MaxUnavailable,
+		MaxSurge:       rollout.MaxSurge,
+		Paused:         rollout.Paused,
+		Canary:         (*v1alpha1.CanaryStrategy)(rollout.Canary),
+	}
+}
+
+// rolloutFromHub converts a rollout strategy from v1alpha1.
+func rolloutFromHub(rollout *v1alpha1.RolloutStrategy) *RolloutStrategy {
+	if rollout == nil {
+		return nil
+	}
+
+	rollout = rollout.DeepCopy()
+	return &RolloutStrategy{
+		Type:           rollout.Type,
+		MaxUnavailable: rollout.MaxUnavailable,
+		MaxSurge:       rollout.MaxSurge,
+		Paused:         rollout.Paused,
+		Canary:         (*CanaryStrategy)(rollout.Canary),
+	}
+}
+
+// interfacesToHub converts structured interface entries into the <key>=<value> entries of v1alpha1.
+func interfacesToHub(interfaces []Interface) []string {
+	if interfaces == nil {
+		return nil
+	}
+
+	entries := make([]string, 0, len(interfaces))
+	for _, iface := range interfaces {
+		switch {
+		case iface.Name != "":
+			entries = append(entries, iface.Name)
+		case iface.PCIAddress != "":
+			entries = append(entries, v1alpha1.InterfaceSelectorPCIAddress+"="+iface.PCIAddress)
+		case iface.ID != "":
+			entries = append(entries, v1alpha1.InterfaceSelectorID+"="+iface.ID)
+		case iface.Driver != "":
+			entries = append(entries, v1alpha1.InterfaceSelectorDriver+"="+iface.Driver)
+		case iface.Bond != "":
+			entries = append(entries, v1alpha1.InterfaceSelectorBond+"="+iface.Bond)
+		default:
+			entries = append(entries, "")
+		}
+	}
+
+	return entries
+}
+
+// interfacesFromHub converts the entries of v1alpha1 into structured interface entries. Entries that are
+// not valid selectors are kept as interface names.
+func interfacesFromHub(entries []string) []Interface {
+	if entries == nil {
+		return nil
+	}
+
+	interfaces := make([]Interface, 0, len(entries))
+	for _, entry := range entries {
+		selector, err := v1alpha1.ParseInterfaceSelector(entry)
+		if err != nil || selector == nil {
+			interfaces = append(interfaces, Interface{Name: entry})
+			continue
+		}
+
+		switch selector.Key {
+		case v1alpha1.InterfaceSelectorPCIAddress:
+			interfaces = append(interfaces, Interface{PCIAddress: selector.Value})
+		case v1alpha1.InterfaceSelectorID:
+			interfaces = append(interfaces, Interface{ID: selector.Value})
+		case v1alpha1.InterfaceSelectorDriver:
+			interfaces = append(interfaces, Interface{Driver: selector.Value})
+		case v1alpha1.InterfaceSelectorBond:
+			interfaces = append(interfaces, Interface{Bond: selector.Value})
+		}
+	}
+
+	return interfaces
+}
+
+// mergeNodeSelectors combines the nodeSelector and nodeLabelSelector of v1alpha1 into a single label selector
+// matching the nodes matching both. A label of nodeSelector conflicting with the matchLabels of nodeLabelSelector
+// is added as a requirement.
+func mergeNodeSelectors(nodeSelector map[string]string, labelSelector *metav1.LabelSelector) *metav1.LabelSelector {
+	if len(nodeSelector) == 0 {
+		return labelSelector.DeepCopy()
+	}
+
+	selector := labelSelector.DeepCopy()
+	if selector == nil {
+		selector = &metav1.LabelSelector{}
+	}
+	if selector.MatchLabels == nil {
+		selector.MatchLabels = make(map[string]string, len(nodeSelector))
+	}
+
+	keys := make([]string, 0, len(nodeSelector))
+	for key := range nodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, found := selector.MatchLabels[key]
+		if found && value != nodeSelector[key] {
+			selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      key,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{nodeSelector[key]},
+			})
+			continue
+		}
+		selector.MatchLabels[key] = nodeSelector[key]
+	}
+
+	return selector
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(labels))
+	for key, value := range labels {
+		copied[key] = value
+	}
+	return copied
+}
+
+func copyConditions(conditions []metav1.Condition) []metav1.Condition {
+	if conditions == nil {
+		return nil
+	}
+
+	copied := make([]metav1.Condition, len(conditions))
+	for i := range conditions {
+		conditions[i].DeepCopyInto(&copied[i])
+	}
+	return copied
+}
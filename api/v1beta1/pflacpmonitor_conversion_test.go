@@ -0,0 +1,267 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+var _ = Describe("PFLACPMonitor conversion", func() {
+	now := metav1.NewTime(time.Date(2024, 8, 9, 21, 48, 3, 0, time.UTC))
+
+	It("is convertible", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(AddToScheme(scheme)).To(Succeed())
+
+		convertible, err := conversion.IsConvertible(scheme, &v1alpha1.PFLACPMonitor{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(convertible).To(BeTrue())
+	})
+
+	It("converts to v1alpha1", func() {
+		monitor := &PFLACPMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: PFLACPMonitorSpec{
+				Interfaces: []Interface{
+					{Name: "ens1f0"},
+					{PCIAddress: "0000:3b:00.*"},
+					{ID: "8086:159b"},
+					{Driver: "ice"},
+					{Bond: "bond0"},
+				},
+				PollingInterval: &metav1.Duration{Duration: 2 * time.Second},
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"node-role.kubernetes.io/worker": ""},
+				},
+			},
+		}
+
+		hub := &v1alpha1.PFLACPMonitor{}
+		Expect(monitor.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Interfaces).To(Equal([]string{"ens1f0", "pci=0000:3b:00.*", "id=8086:159b", "driver=ice", "bond=bond0"}))
+		Expect(hub.Spec.PollingInterval).To(Equal(2000))
+		Expect(hub.Spec.NodeSelector).To(BeNil())
+		Expect(hub.Spec.NodeLabelSelector).To(Equal(monitor.Spec.NodeSelector))
+	})
+
+	It("round-trips through v1alpha1", func() {
+		maxUnavailable := intstr.FromString("10%")
+		monitor := &PFLACPMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "monitor",
+				Namespace:  "default",
+				Generation: 3,
+				Labels:     map[string]string{"app.kubernetes.io/name": "pf-status-relay-operator"},
+			},
+			Spec: PFLACPMonitorSpec{
+				Interfaces:      []Interface{{Name: "ens1f0"}, {Driver: "ice"}},
+				PollingInterval: &metav1.Duration{Duration: 500 * time.Millisecond},
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"node-role.kubernetes.io/worker": ""},
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+					},
+				},
+				NodeOverrides: []NodeInterfaceOverride{
+					{
+						Name:         "sku-b",
+						NodeSelector: map[string]string{"example.com/sku": "b"},
+						Interfaces:   []Interface{{PCIAddress: "0000:5e:00.0"}, {ID: "15b3:1017"}, {Bond: "bond1"}},
+					},
+				},
+				Priority: 10,
+				RelayPod: &RelayPodTemplate{
+					Labels:            map[string]string{"team": "network"},
+					Tolerations:       []corev1.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists}},
+					PriorityClassName: "system-cluster-critical",
+				},
+				Rollout: &RolloutStrategy{
+					MaxUnavailable: &maxUnavailable,
+					Canary: &CanaryStrategy{
+						NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"example.com/canary": "true"}},
+						SoakTime:     &metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+				LogLevel: v1alpha1.LogLevelInfo,
+				LogLevelOverride: &LogLevelOverride{
+					Level: v1alpha1.LogLevelDebug,
+					Until: now,
+				},
+			},
+			Status: PFLACPMonitorStatus{
+				ObservedGeneration: 3,
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.ConditionAvailable, Status: metav1.ConditionTrue, Reason: v1alpha1.ReasonRelayPodsReady, LastTransitionTime: now},
+				},
+				DesiredNumberScheduled: 2,
+				NumberReady:            1,
+				UpdatedNumberScheduled: 2,
+				NumberUnavailable:      1,
+				UnreadyNodes:           []NodeRelayStatus{{NodeName: "worker-1", PodName: "pf-status-relay-ds-monitor-x", Reason: "CrashLoopBackOff"}},
+				Interfaces: []InterfaceStatus{
+					{NodeName: "worker-0", Name: "ens1f0", LACP: v1alpha1.LACPStateDown, VFLinkState: "disable", LastTransitionTime: now},
+				},
+				WinningMonitor: "default/other-monitor",
+				Rollout: &RolloutStatus{
+					Phase:              v1alpha1.RolloutPhaseSoaking,
+					Revision:           "5d8f9c7b",
+					CanaryNodes:        1,
+					UpdatedCanaryNodes: 1,
+					CanaryReadyTime:    &now,
+				},
+			},
+		}
+
+		hub := &v1alpha1.PFLACPMonitor{}
+		Expect(monitor.ConvertTo(hub)).To(Succeed())
+
+		converted := &PFLACPMonitor{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted).To(Equal(monitor))
+	})
+
+	It("round-trips v1alpha1 monitors", func() {
+		hub := &v1alpha1.PFLACPMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "monitor",
+				Namespace:   "default",
+				Annotations: map[string]string{"example.com/owner": "admin"},
+			},
+			Spec: v1alpha1.PFLACPMonitorSpec{
+				Interfaces:      []string{"ens1f0", "driver=ice", "id=8086:159b", "mac=00:11:22:33:44:55"},
+				PollingInterval: 1500,
+				NodeSelector:    map[string]string{"node-role.kubernetes.io/worker": ""},
+				NodeLabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+					},
+				},
+				NodeOverrides: []v1alpha1.NodeInterfaceOverride{
+					{
+						Name:         "sku-b",
+						NodeSelector: map[string]string{"example.com/sku": "b"},
+						Interfaces:   []string{"pci=0000:5e:00.0", "bond=bond1"},
+					},
+				},
+				Priority: 5,
+			},
+		}
+
+		monitor := &PFLACPMonitor{}
+		Expect(monitor.ConvertFrom(hub)).To(Succeed())
+		Expect(monitor.Annotations).To(HaveKey(NodeSelectorsAnnotation))
+
+		converted := &v1alpha1.PFLACPMonitor{}
+		Expect(monitor.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(hub))
+	})
+
+	DescribeTable("round-trips v1alpha1 node selectors",
+		func(nodeSelector map[string]string, labelSelector *metav1.LabelSelector, expected *metav1.LabelSelector) {
+			hub := &v1alpha1.PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+				Spec: v1alpha1.PFLACPMonitorSpec{
+					Interfaces:        []string{"eth0"},
+					NodeSelector:      nodeSelector,
+					NodeLabelSelector: labelSelector,
+				},
+			}
+
+			monitor := &PFLACPMonitor{}
+			Expect(monitor.ConvertFrom(hub)).To(Succeed())
+			Expect(monitor.Spec.NodeSelector).To(Equal(expected))
+
+			converted := &v1alpha1.PFLACPMonitor{}
+			Expect(monitor.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+
+			By("selecting the same nodes once the v1beta1 selector is changed")
+			if monitor.Spec.NodeSelector == nil {
+				monitor.Spec.NodeSelector = &metav1.LabelSelector{}
+			}
+			monitor.Spec.NodeSelector.MatchExpressions = append(monitor.Spec.NodeSelector.MatchExpressions,
+				metav1.LabelSelectorRequirement{Key: "d", Operator: metav1.LabelSelectorOpDoesNotExist})
+			converted = &v1alpha1.PFLACPMonitor{}
+			Expect(monitor.ConvertTo(converted)).To(Succeed())
+			Expect(converted.Annotations).NotTo(HaveKey(NodeSelectorsAnnotation))
+			Expect(converted.Spec.NodeSelector).To(BeNil())
+			Expect(converted.Spec.NodeLabelSelector).To(Equal(monitor.Spec.NodeSelector))
+
+			// The nodes are selected the same way, even if the selector moved to nodeLabelSelector
+			selector, err := v1alpha1.NodeSelector(hub)
+			Expect(err).NotTo(HaveOccurred())
+			convertedSelector, err := v1alpha1.NodeSelector(converted)
+			Expect(err).NotTo(HaveOccurred())
+			for _, nodeLabels := range []labels.Set{
+				{},
+				{"a": "1"},
+				{"a": "1", "b": ""},
+				{"a": "1", "b": "", "c": "3"},
+				{"a": "1", "b": "", "c": "4"},
+			} {
+				Expect(convertedSelector.Matches(nodeLabels)).To(Equal(selector.Matches(nodeLabels)), "labels %v", nodeLabels)
+			}
+		},
+		Entry("no selector", nil, nil, nil),
+		Entry("node selector",
+			map[string]string{"a": "1"}, nil,
+			&metav1.LabelSelector{MatchLabels: map[string]string{"a": "1"}}),
+		Entry("label selector",
+			nil, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "b", Operator: metav1.LabelSelectorOpExists}}},
+			&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "b", Operator: metav1.LabelSelectorOpExists}}}),
+		Entry("both selectors",
+			map[string]string{"a": "1", "c": "3"},
+			&metav1.LabelSelector{
+				MatchLabels:      map[string]string{"c": "4"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "b", Operator: metav1.LabelSelectorOpExists}},
+			},
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"a": "1", "c": "4"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "b", Operator: metav1.LabelSelectorOpExists},
+					{Key: "c", Operator: metav1.LabelSelectorOpIn, Values: []string{"3"}},
+				},
+			}),
+	)
+
+	It("keeps invalid v1alpha1 entries as interface names", func() {
+		hub := &v1alpha1.PFLACPMonitor{
+			Spec: v1alpha1.PFLACPMonitorSpec{Interfaces: []string{"mac=00:11:22:33:44:55"}},
+		}
+
+		monitor := &PFLACPMonitor{}
+		Expect(monitor.ConvertFrom(hub)).To(Succeed())
+		Expect(monitor.Spec.Interfaces).To(Equal([]Interface{{Name: "mac=00:11:22:33:44:55"}}))
+
+		converted := &v1alpha1.PFLACPMonitor{}
+		Expect(monitor.ConvertTo(converted)).To(Succeed())
+		Expect(converted.Spec.Interfaces).To(Equal(hub.Spec.Interfaces))
+	})
+})
@@ -22,6 +22,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 
@@ -30,20 +32,25 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	openshifttls "github.com/openshift/controller-runtime-common/pkg/tls"
 	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	pfstatusrelayv1beta1 "github.com/openshift/pf-status-relay-operator/api/v1beta1"
 	"github.com/openshift/pf-status-relay-operator/internal/controller"
+	"github.com/openshift/pf-status-relay-operator/internal/discovery"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+	"github.com/openshift/pf-status-relay-operator/internal/tlsprofile"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -59,6 +66,7 @@ func init() {
 	utilruntime.Must(configv1.Install(scheme))
 
 	utilruntime.Must(pfstatusrelayv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(pfstatusrelayv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -72,6 +80,13 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var metricsCertDir string
+	var nodeAgent bool
+	var nodeAgentInterval time.Duration
+	var tlsProfile string
+	var tlsMinVersion string
+	var tlsCipherSuites string
+	var logLevel string
+	var logFormat string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -84,13 +99,37 @@ func main() {
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.StringVar(&metricsCertDir, "metrics-cert-dir", "",
 		"Directory containing tls.crt and tls.key for the metrics server. If empty, a self-signed certificate is generated.")
-	opts := zap.Options{
-		Development: true,
-	}
-	opts.BindFlags(flag.CommandLine)
+	flag.BoolVar(&nodeAgent, "node-agent", false,
+		"If set, run the node agent reporting the PFs of the node in its PFLACPNodeState instead of the manager.")
+	flag.DurationVar(&nodeAgentInterval, "node-agent-interval", 30*time.Second,
+		"The time between two discoveries of the node agent.")
+	flag.StringVar(&tlsProfile, "tls-profile", string(configv1.TLSProfileIntermediateType),
+		"The TLS profile of the metrics and webhook servers on clusters without the config.openshift.io API, one of "+
+			"Old, Intermediate, Modern or Custom. The tlsSecurityProfile of the operator config takes precedence.")
+	flag.StringVar(&tlsMinVersion, "tls-min-version", string(configv1.VersionTLS12),
+		"The minimum TLS version of the Custom TLS profile.")
+	flag.StringVar(&tlsCipherSuites, "tls-cipher-suites", "",
+		"Comma-separated list of the ciphers of the Custom TLS profile. If empty, the ciphers of the Intermediate profile are used.")
+	flag.StringVar(&logLevel, "log-level", pfstatusrelayv1alpha1.LogLevelInfo,
+		"The log level of the operator, one of debug, info, warn or error. The logLevel of the operator config takes precedence.")
+	flag.StringVar(&logFormat, "log-format", pfstatusrelayv1alpha1.LogFormatJSON,
+		"The log format of the operator, json or text. The logFormat of the operator config takes precedence.")
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if err := log.Configure(logLevel, logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid log flags: %v\n", err)
+		os.Exit(1)
+	}
+	// controller-runtime and the reconcilers share the same logger
+	ctrl.SetLogger(log.Logr())
+
+	if nodeAgent {
+		if err := runNodeAgent(nodeAgentInterval); err != nil {
+			setupLog.Error(err, "problem running node agent")
+			os.Exit(1)
+		}
+		return
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -103,10 +142,10 @@ func main() {
 		c.NextProtos = []string{"http/1.1"}
 	}
 
-	// Fetch TLS profile from apiservers.config.openshift.io/cluster before the
-	// manager starts so the initial TLS config can be applied to all servers.
-	ctx, cancel := context.WithCancel(ctrl.SetupSignalHandler())
-	defer cancel()
+	// Fetch TLS profile from apiservers.config.openshift.io/cluster, or from the
+	// operator config and flags on other clusters, before the manager starts so
+	// the initial TLS config can be applied to all servers.
+	ctx := ctrl.SetupSignalHandler()
 
 	cfg := ctrl.GetConfigOrDie()
 
@@ -116,23 +155,47 @@ func main() {
 		os.Exit(1)
 	}
 
-	// TODO: gate behind API discovery so the operator can fall back to default TLS on vanilla k8s / kind.
-	tlsProfileSpec, err := openshifttls.FetchAPIServerTLSProfile(ctx, tempClient)
+	openShift, err := tlsprofile.APIServerAvailable(tempClient.RESTMapper())
 	if err != nil {
-		setupLog.Error(err, "unable to fetch TLS profile from APIServer")
+		setupLog.Error(err, "unable to discover the cluster APIs")
 		os.Exit(1)
 	}
 
-	tlsProfileOpt, unsupported := openshifttls.NewTLSConfigFromProfile(tlsProfileSpec)
-	if len(unsupported) > 0 {
-		setupLog.Info("TLS profile contains ciphers unsupported by Go crypto/tls", "ciphers", unsupported)
+	var tlsProfileSpec configv1.TLSProfileSpec
+	var flagProfile *configv1.TLSSecurityProfile
+	if openShift {
+		tlsProfileSpec, err = openshifttls.FetchAPIServerTLSProfile(ctx, tempClient)
+		if err != nil {
+			setupLog.Error(err, "unable to fetch TLS profile from APIServer")
+			os.Exit(1)
+		}
+	} else {
+		var ciphers []string
+		if tlsCipherSuites != "" {
+			ciphers = strings.Split(tlsCipherSuites, ",")
+		}
+		flagProfile, err = tlsprofile.FromFlags(tlsProfile, tlsMinVersion, ciphers)
+		if err != nil {
+			setupLog.Error(err, "invalid TLS profile flags")
+			os.Exit(1)
+		}
+		tlsProfileSpec, err = tlsprofile.FetchFallbackProfile(ctx, tempClient, flagProfile)
+		if err != nil {
+			setupLog.Error(err, "unable to fetch TLS profile from operator config")
+			os.Exit(1)
+		}
+		setupLog.Info("config.openshift.io API not found, using the TLS profile of the operator config or flags",
+			"minTLSVersion", tlsProfileSpec.MinTLSVersion)
 	}
 
+	// The TLS profile is applied to every handshake, so that its changes apply without restarting the servers
+	tlsProfileReloader := tlsprofile.NewReloader(tlsProfileSpec)
+
 	tlsOpts := []func(*tls.Config){}
 	if !enableHTTP2 {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
-	tlsOpts = append(tlsOpts, tlsProfileOpt)
+	tlsOpts = append(tlsOpts, tlsProfileReloader.TLSOpt)
 
 	webhookServer := webhook.NewServer(webhook.Options{
 		TLSOpts: tlsOpts,
@@ -191,8 +254,9 @@ func main() {
 	}
 
 	if err = (&controller.PFLACPMonitorReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("pflacpmonitor-controller"), //nolint:staticcheck // events are recorded through the core/v1 API
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PFLACPMonitor")
 		os.Exit(1)
@@ -204,17 +268,31 @@ func main() {
 		}
 	}
 
-	// Watch apiservers.config.openshift.io/cluster and cancel the context
-	// (triggering a graceful shutdown) when the TLS profile changes. The
-	// deployment controller will restart the pod with the new configuration.
-	if err = (&openshifttls.SecurityProfileWatcher{
-		Client:                mgr.GetClient(),
-		InitialTLSProfileSpec: tlsProfileSpec,
-		OnProfileChange: func(_ context.Context, _, _ configv1.TLSProfileSpec) {
-			cancel()
-		},
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to set up TLS profile watcher")
+	// Watch apiservers.config.openshift.io/cluster and apply the TLS profile
+	// to the webhook and metrics servers when it changes. On other clusters,
+	// the profile of the operator config is applied by its controller.
+	if openShift {
+		if err = (&openshifttls.SecurityProfileWatcher{
+			Client:                mgr.GetClient(),
+			InitialTLSProfileSpec: tlsProfileSpec,
+			OnProfileChange: func(_ context.Context, _, newProfile configv1.TLSProfileSpec) {
+				tlsProfileReloader.Set(newProfile)
+			},
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up TLS profile watcher")
+			os.Exit(1)
+		}
+	}
+	operatorConfigReconciler := &controller.OperatorConfigReconciler{
+		Client:    mgr.GetClient(),
+		Namespace: watchNamespace,
+	}
+	if !openShift {
+		operatorConfigReconciler.TLSProfile = tlsProfileReloader
+		operatorConfigReconciler.FallbackTLSProfile = flagProfile
+	}
+	if err = operatorConfigReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PFStatusRelayOperatorConfig")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
@@ -242,3 +320,55 @@ func getWatchNamespace() (string, error) {
 	}
 	return string(podNS), nil
 }
+
+// runNodeAgent reports the PFs of the node the agent runs on until it is stopped.
+func runNodeAgent(interval time.Duration) error {
+	namespace, err := getWatchNamespace()
+	if err != nil {
+		return err
+	}
+
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		return fmt.Errorf("NODE_NAME is not set")
+	}
+
+	// The agent of every node syncs periodically, so it reads from a cache holding its own node and node state
+	// and the monitors of the operator namespace instead of querying the API server on every sync
+	cfg := ctrl.GetConfigOrDie()
+	nodeSelector := fields.OneTermEqualSelector("metadata.name", nodeName)
+	agentCache, err := cache.New(cfg, cache.Options{
+		Scheme: scheme,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Node{}:                           {Field: nodeSelector},
+			&pfstatusrelayv1alpha1.PFLACPNodeState{}: {Field: nodeSelector},
+			&pfstatusrelayv1alpha1.PFLACPMonitor{}: {
+				Namespaces: map[string]cache.Config{namespace: {}},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create cache: %w", err)
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme, Cache: &client.CacheOptions{Reader: agentCache}})
+	if err != nil {
+		return fmt.Errorf("unable to create client: %w", err)
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	go func() {
+		if err := agentCache.Start(ctx); err != nil {
+			setupLog.Error(err, "problem running node agent cache")
+		}
+	}()
+
+	setupLog.Info("starting node agent", "node", nodeName)
+	return (&discovery.Agent{
+		Client:    c,
+		NodeName:  nodeName,
+		Namespace: namespace,
+		SysfsRoot: "/sys",
+		Interval:  interval,
+	}).Start(ctx)
+}
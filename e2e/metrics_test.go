@@ -18,6 +18,7 @@ package e2e
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -29,7 +30,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const metricsURL = `https://pf-status-relay-operator-controller-manager-metrics-service.` + operatorNS + `.svc:8443/metrics`
+const (
+	metricsURL     = `https://pf-status-relay-operator-controller-manager-metrics-service.` + operatorNS + `.svc:8443/metrics`
+	thanosQueryURL = `https://thanos-querier.openshift-monitoring.svc:9091/api/v1/query`
+)
 
 var _ = Describe("metrics endpoint", Label("e2e", "metrics"), func() {
 
@@ -79,11 +83,43 @@ var _ = Describe("metrics endpoint", Label("e2e", "metrics"), func() {
 		logs, err := probe.RunPod(ctx, cmd)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(string(logs)).To(ContainSubstring("go_goroutines"))
+		Expect(string(logs)).To(ContainSubstring("pf_status_relay_operator_monitors"))
 	})
 
-	XIt("metrics are scraped by Prometheus", func(_ context.Context) {
-		// TODO: ServiceMonitor is not included in the OLM bundle manifests so
-		// Prometheus does not scrape the operator when deployed via OLM.
-		// Add ServiceMonitor to bundle/manifests before implementing this test.
+	It("metrics are scraped by Prometheus", func(ctx context.Context) {
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name: "e2e-monitoring-viewer", Namespace: operatorNS, Labels: e2eLabels,
+		}}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, sa))).To(Succeed())
+
+		crb := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "e2e-monitoring-viewer", Labels: e2eLabels},
+			Subjects: []rbacv1.Subject{{
+				Kind:      "ServiceAccount",
+				Name:      sa.Name,
+				Namespace: operatorNS,
+			}},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     "cluster-monitoring-view",
+			},
+		}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, crb))).To(Succeed())
+
+		tr, err := clientset.CoreV1().ServiceAccounts(operatorNS).
+			CreateToken(ctx, sa.Name, &authv1.TokenRequest{
+				Spec: authv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(900))},
+			}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The metric is only returned once Prometheus has scraped the operator through the ServiceMonitor
+		cmd := `curl -sG --cacert /etc/cabundle/service-ca.crt -H "Authorization: Bearer ` + tr.Status.Token + `" ` +
+			`--data-urlencode 'query=pf_status_relay_operator_monitors{namespace="` + operatorNS + `"}' ` + thanosQueryURL + ` 2>&1`
+		Eventually(func(g Gomega) {
+			logs, err := probe.RunPod(ctx, cmd)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(logs)).To(ContainSubstring(`"__name__":"pf_status_relay_operator_monitors"`))
+		}).WithContext(ctx).WithTimeout(5 * time.Minute).WithPolling(15 * time.Second).Should(Succeed())
 	})
 })
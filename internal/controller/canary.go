@@ -0,0 +1,259 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+)
+
+// defaultCanarySoakTime is the time the relay pods of the canary nodes must stay ready when the monitor sets none.
+const defaultCanarySoakTime = 5 * time.Minute
+
+// canaryRollout checks whether the relay pods of the monitor are replaced by the operator, canary nodes first.
+func canaryRollout(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) bool {
+	rollout := pfMonitor.Spec.Rollout
+	return rollout != nil && rollout.Canary != nil && !rollout.Paused && rollout.Type != appsv1.OnDeleteDaemonSetStrategyType
+}
+
+// relayPod is a relay pod of the monitor during a canary rollout.
+type relayPod struct {
+	pod    *corev1.Pod
+	node   string
+	canary bool
+	// updated is true when the pod runs the latest revision of its DaemonSet
+	updated bool
+}
+
+// rolloutCanary replaces the relay pods not running the latest revision of their DaemonSet, canary nodes first.
+// The other nodes are only updated once the relay pods of all the canary nodes have been ready with the latest
+// revisions for the soak time, replacing at most maxUnavailable ready pods at a time. It returns the progress of
+// the rollout and, while soaking, the time left until the soak time ends.
+func (r *PFLACPMonitorReconciler) rolloutCanary(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, daemonSets []*appsv1.DaemonSet, nodeList *corev1.NodeList) (*pfstatusrelayv1alpha1.RolloutStatus, time.Duration, error) {
+	rollout := pfMonitor.Spec.Rollout
+	selector, err := metav1.LabelSelectorAsSelector(&rollout.Canary.NodeSelector)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid canary node selector: %w", err)
+	}
+	canaryNodes := sets.New[string]()
+	for _, node := range nodeList.Items {
+		if selector.Matches(labels.Set(node.Labels)) {
+			canaryNodes.Insert(node.Name)
+		}
+	}
+
+	status := &pfstatusrelayv1alpha1.RolloutStatus{}
+	revisions := make([]string, 0, len(daemonSets))
+	var pods []relayPod
+	var desired, unavailable int32
+	for _, ds := range daemonSets {
+		revision, err := r.currentRevision(ctx, ds)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get daemon set revision: %w", err)
+		}
+		// The DaemonSet controller records the revision once it observes the DaemonSet
+		if revision == "" || ds.Status.ObservedGeneration < ds.Generation {
+			status.Phase = pfstatusrelayv1alpha1.RolloutPhaseCanary
+			status.Message = fmt.Sprintf("waiting for the revision of DaemonSet %s", ds.Name)
+			return status, 0, nil
+		}
+		revisions = append(revisions, ds.Name+"="+revision)
+		desired += ds.Status.DesiredNumberScheduled
+		unavailable += ds.Status.NumberUnavailable
+
+		podList := &corev1.PodList{}
+		err = r.List(ctx, podList, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list relay pods: %w", err)
+		}
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if !metav1.IsControlledBy(pod, ds) {
+				continue
+			}
+			node := podNodeName(pod)
+			pods = append(pods, relayPod{
+				pod:     pod,
+				node:    node,
+				canary:  canaryNodes.Has(node),
+				updated: pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] == revision,
+			})
+		}
+	}
+	status.Revision = pfstatusrelayv1alpha1.NameHash(strings.Join(revisions, ","))
+
+	canaries := sets.New[string]()
+	updatedCanaries := sets.New[string]()
+	var outdatedCanaries, outdated []relayPod
+	var notReady int32
+	for _, p := range pods {
+		if p.canary {
+			canaries.Insert(p.node)
+		}
+		ready := p.pod.DeletionTimestamp == nil && isPodReady(p.pod)
+		if !ready {
+			notReady++
+		}
+		switch {
+		case p.pod.DeletionTimestamp != nil:
+		case !p.updated && p.canary:
+			outdatedCanaries = append(outdatedCanaries, p)
+		case !p.updated:
+			outdated = append(outdated, p)
+		case p.canary && ready:
+			updatedCanaries.Insert(p.node)
+		}
+	}
+	status.CanaryNodes = int32(canaries.Len())
+	status.UpdatedCanaryNodes = int32(updatedCanaries.Len())
+
+	previous := pfMonitor.Status.Rollout
+	if previous != nil && previous.Revision == status.Revision {
+		status.CanaryReadyTime = previous.CanaryReadyTime
+	}
+
+	if canaries.Len() == 0 {
+		status.Phase = pfstatusrelayv1alpha1.RolloutPhaseCanary
+		status.CanaryReadyTime = nil
+		status.Message = "no relay pod runs on a node matching the canary node selector"
+		return status, 0, nil
+	}
+
+	if err = r.deleteRelayPods(ctx, outdatedCanaries); err != nil {
+		return nil, 0, err
+	}
+	if updatedCanaries.Len() < canaries.Len() {
+		status.Phase = pfstatusrelayv1alpha1.RolloutPhaseCanary
+		status.CanaryReadyTime = nil
+		status.Message = fmt.Sprintf("%d of %d canary nodes run a ready relay pod of the latest revision", updatedCanaries.Len(), canaries.Len())
+		return status, 0, nil
+	}
+
+	now := metav1.Now()
+	if status.CanaryReadyTime == nil {
+		status.CanaryReadyTime = &now
+	}
+	soakTime := defaultCanarySoakTime
+	if rollout.Canary.SoakTime != nil {
+		soakTime = rollout.Canary.SoakTime.Duration
+	}
+	soakEnd := status.CanaryReadyTime.Add(soakTime)
+	if remaining := soakEnd.Sub(now.Time); remaining > 0 {
+		status.Phase = pfstatusrelayv1alpha1.RolloutPhaseSoaking
+		status.Message = fmt.Sprintf("the relay pods of the canary nodes must stay ready until %s", soakEnd.UTC().Format(time.RFC3339))
+		return status, remaining, nil
+	}
+
+	if len(outdated) == 0 {
+		status.Phase = pfstatusrelayv1alpha1.RolloutPhaseComplete
+		return status, 0, nil
+	}
+
+	if previous != nil && previous.Phase == pfstatusrelayv1alpha1.RolloutPhaseSoaking {
+		r.Recorder.Eventf(pfMonitor, corev1.EventTypeNormal, pfstatusrelayv1alpha1.EventReasonCanaryPromoted,
+			"Rolling out revision %s to the other nodes", status.Revision)
+	}
+
+	maxUnavailable := intstr.FromInt32(1)
+	if rollout.MaxUnavailable != nil {
+		maxUnavailable = *rollout.MaxUnavailable
+	}
+	budget, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(desired), true)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid maxUnavailable: %w", err)
+	}
+	budget = max(budget, 1) - int(max(unavailable, notReady))
+
+	// Replacing the pods that are not ready does not lower the availability, so they go first
+	sort.Slice(outdated, func(i, j int) bool {
+		iReady, jReady := isPodReady(outdated[i].pod), isPodReady(outdated[j].pod)
+		if iReady != jReady {
+			return !iReady
+		}
+		return outdated[i].node < outdated[j].node
+	})
+	var replaced []relayPod
+	for _, p := range outdated {
+		if isPodReady(p.pod) {
+			if budget <= 0 {
+				break
+			}
+			budget--
+		}
+		replaced = append(replaced, p)
+	}
+	if err = r.deleteRelayPods(ctx, replaced); err != nil {
+		return nil, 0, err
+	}
+
+	status.Phase = pfstatusrelayv1alpha1.RolloutPhaseRollingOut
+	status.Message = fmt.Sprintf("%d relay pods of the other nodes run an older revision", len(outdated))
+	return status, 0, nil
+}
+
+// currentRevision returns the hash of the latest revision of a DaemonSet, or an empty string when the
+// DaemonSet controller has not recorded any yet.
+func (r *PFLACPMonitorReconciler) currentRevision(ctx context.Context, ds *appsv1.DaemonSet) (string, error) {
+	revisionList := &appsv1.ControllerRevisionList{}
+	err := r.List(ctx, revisionList, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels))
+	if err != nil {
+		return "", err
+	}
+
+	var latest *appsv1.ControllerRevision
+	for i := range revisionList.Items {
+		revision := &revisionList.Items[i]
+		if !metav1.IsControlledBy(revision, ds) {
+			continue
+		}
+		if latest == nil || revision.Revision > latest.Revision {
+			latest = revision
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+
+	return latest.Labels[appsv1.DefaultDaemonSetUniqueLabelKey], nil
+}
+
+// deleteRelayPods deletes relay pods, for their DaemonSet to recreate them with its latest revision.
+func (r *PFLACPMonitorReconciler) deleteRelayPods(ctx context.Context, pods []relayPod) error {
+	for _, p := range pods {
+		log.FromContext(ctx).Info("replacing relay pod", "pod", p.pod.Name, "node", p.node, "canary", p.canary)
+		err := r.Delete(ctx, p.pod, client.Preconditions{UID: &p.pod.UID})
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete relay pod %s: %w", p.pod.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/discovery"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+	"github.com/openshift/pf-status-relay-operator/internal/metrics"
+)
+
+// interfaceClaimsFinalizer keeps a monitor until its interface claims are released.
+const interfaceClaimsFinalizer = "pfstatusrelay.openshift.io/interface-claims"
+
+// claimInterfaces records the interfaces the monitor monitors on each selected node in the PFInterfaceClaim
+// of the node, and releases the ones it no longer monitors. The claims are cluster-scoped, so that an interface
+// is monitored once per node whatever the namespace of the monitor. If a monitor taking precedence over this one
+// already claims one of the interfaces, it returns an *InterfaceClaimConflict without claiming anything. The
+// interfaces claimed by the monitors it takes precedence over are taken over.
+func (r *PFLACPMonitorReconciler) claimInterfaces(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, nodeList *corev1.NodeList, inventory map[string][]pfstatusrelayv1alpha1.PFStatus) error {
+	hostInventory := make(map[string][]pfstatusrelayv1alpha1.HostInterface, len(inventory))
+	for node, pfs := range inventory {
+		hostInventory[node] = discovery.HostInterfaces(pfs)
+	}
+
+	claims, err := pfstatusrelayv1alpha1.NodeInterfaceClaims(pfMonitor, nodeList, hostInventory)
+	if err != nil {
+		return err
+	}
+
+	claimList := &pfstatusrelayv1alpha1.PFInterfaceClaimList{}
+	if err = r.List(ctx, claimList); err != nil {
+		return err
+	}
+
+	// Check every node before claiming anything, so that a conflicting monitor does not hold part of its interfaces
+	if err = pfstatusrelayv1alpha1.ClaimConflict(pfMonitor, claims, claimList); err != nil {
+		return err
+	}
+
+	nodes := make(map[string]*corev1.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+	}
+
+	return r.syncInterfaceClaims(ctx, pfMonitor, claims, claimList, nodes)
+}
+
+// releaseInterfaces releases all the interfaces claimed by the monitor.
+func (r *PFLACPMonitorReconciler) releaseInterfaces(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) error {
+	claimList := &pfstatusrelayv1alpha1.PFInterfaceClaimList{}
+	if err := r.List(ctx, claimList); err != nil {
+		return err
+	}
+
+	return r.syncInterfaceClaims(ctx, pfMonitor, nil, claimList, nil)
+}
+
+// syncInterfaceClaims sets the interfaces claimed by the monitor on each node to claims, on the nodes of
+// claims and on the nodes of claimList.
+func (r *PFLACPMonitorReconciler) syncInterfaceClaims(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, claims map[string][]string, claimList *pfstatusrelayv1alpha1.PFInterfaceClaimList, nodes map[string]*corev1.Node) error {
+	names := sets.New[string]()
+	for node := range claims {
+		names.Insert(node)
+	}
+	for _, claim := range claimList.Items {
+		names.Insert(claim.Name)
+	}
+
+	for _, name := range sets.List(names) {
+		err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+			return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+		}, func() error {
+			return r.syncInterfaceClaim(ctx, pfMonitor, name, claims[name], nodes[name])
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncInterfaceClaim sets the interfaces claimed by the monitor in the PFInterfaceClaim of a node. The claim
+// is created when missing, owned by its node, and deleted once it holds no interface.
+func (r *PFLACPMonitorReconciler) syncInterfaceClaim(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, name string, interfaces []string, node *corev1.Node) error {
+	claim := &pfstatusrelayv1alpha1.PFInterfaceClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, claim)
+	if err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get interface claim: %w", err)
+		}
+		if len(interfaces) == 0 {
+			return nil
+		}
+
+		claim = &pfstatusrelayv1alpha1.PFInterfaceClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if node != nil {
+			if err = controllerutil.SetOwnerReference(node, claim, r.Scheme); err != nil {
+				return fmt.Errorf("failed to set owner reference: %w", err)
+			}
+		}
+		if err = pfstatusrelayv1alpha1.SetInterfaceClaims(claim, pfMonitor, interfaces); err != nil {
+			return err
+		}
+
+		log.FromContext(ctx).Info("claiming interfaces", "node", name, "interfaces", interfaces)
+		return r.Create(ctx, claim)
+	}
+
+	spec := claim.Spec.DeepCopy()
+	if err = pfstatusrelayv1alpha1.SetInterfaceClaims(claim, pfMonitor, interfaces); err != nil {
+		return err
+	}
+
+	if len(claim.Spec.Claims) == 0 {
+		log.FromContext(ctx).Info("deleting interface claim", "node", name)
+		err = r.Delete(ctx, claim, client.Preconditions{ResourceVersion: &claim.ResourceVersion})
+		return client.IgnoreNotFound(err)
+	}
+
+	if equality.Semantic.DeepEqual(spec, &claim.Spec) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("claiming interfaces", "node", name, "interfaces", interfaces)
+	return r.Update(ctx, claim)
+}
+
+// interfaceClaimMetrics records the number of interfaces claimed on each node from the PFInterfaceClaim events,
+// so that the claims are not listed on every reconcile. The series of a node is removed with its claim, including
+// when the claim is garbage collected with the node.
+var interfaceClaimMetrics = handler.Funcs{
+	CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		recordInterfaceClaims(e.Object)
+	},
+	UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		recordInterfaceClaims(e.ObjectNew)
+	},
+	DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		metrics.SetInterfaceClaims(e.Object.GetName(), 0)
+	},
+}
+
+// recordInterfaceClaims records the number of interfaces claimed by a PFInterfaceClaim on its node.
+func recordInterfaceClaims(obj client.Object) {
+	if claim, ok := obj.(*pfstatusrelayv1alpha1.PFInterfaceClaim); ok {
+		metrics.SetInterfaceClaims(claim.Name, len(claim.Spec.Claims))
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/discovery"
+)
+
+// getInventory returns the PFs found on each node by the node agent.
+func (r *PFLACPMonitorReconciler) getInventory(ctx context.Context) (map[string][]pfstatusrelayv1alpha1.PFStatus, error) {
+	nodeStateList := &pfstatusrelayv1alpha1.PFLACPNodeStateList{}
+	if err := r.List(ctx, nodeStateList); err != nil {
+		return nil, err
+	}
+
+	inventory := make(map[string][]pfstatusrelayv1alpha1.PFStatus, len(nodeStateList.Items))
+	for _, nodeState := range nodeStateList.Items {
+		// Node states that were never synced do not tell anything about the node
+		if nodeState.Status.LastUpdateTime == nil {
+			continue
+		}
+		inventory[nodeState.Name] = nodeState.Status.Interfaces
+	}
+
+	return inventory, nil
+}
+
+// resolveRelayGroups replaces the relay groups whose interfaces contain selectors by one group per set of
+// interfaces the selectors resolve to, pinned to the nodes where they do. It also returns the interfaces
+// not found on each selected node. Nodes without inventory are only checked for their selectors, which
+// cannot be resolved there, so they are left out of the resolved groups.
+func resolveRelayGroups(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, groups []relayGroup, nodeList *corev1.NodeList, inventory map[string][]pfstatusrelayv1alpha1.PFStatus) ([]relayGroup, map[string][]string, error) {
+	missing := make(map[string][]string)
+	// Nodes of each relay group with selectors, by the interfaces they resolve to
+	resolved := make(map[string]map[string][]string)
+
+	for _, node := range nodeList.Items {
+		entries, override, err := pfstatusrelayv1alpha1.NodeInterfaces(pfMonitor, &node)
+		if err != nil {
+			return nil, nil, err
+		}
+		if entries == nil {
+			continue
+		}
+
+		pfs, known := inventory[node.Name]
+		ifaces := discovery.HostInterfaces(pfs)
+		if notFound := missingInterfaces(entries, ifaces, known); len(notFound) > 0 {
+			missing[node.Name] = notFound
+		}
+
+		if !pfstatusrelayv1alpha1.HasInterfaceSelectors(entries) || !known {
+			continue
+		}
+
+		names, err := pfstatusrelayv1alpha1.ResolveInterfaces(entries, ifaces)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		if resolved[override] == nil {
+			resolved[override] = make(map[string][]string)
+		}
+		key := strings.Join(names, ",")
+		resolved[override][key] = append(resolved[override][key], node.Name)
+	}
+
+	result := make([]relayGroup, 0, len(groups))
+	for _, group := range groups {
+		if !pfstatusrelayv1alpha1.HasInterfaceSelectors(group.interfaces) {
+			result = append(result, group)
+			continue
+		}
+
+		keys := make([]string, 0, len(resolved[group.override]))
+		for key := range resolved[group.override] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			result = append(result, relayGroup{
+				name:       pfstatusrelayv1alpha1.SelectorDaemonSetName(group.name, key),
+				override:   group.override,
+				interfaces: strings.Split(key, ","),
+				affinity:   nodeNameAffinity(resolved[group.override][key]),
+			})
+		}
+	}
+
+	return result, missing, nil
+}
+
+// missingInterfaces returns the entries of an interface list not found among the interfaces of a node:
+// the interface names it does not have and the selectors matching none of its interfaces.
+// Interface names are only checked when the interfaces of the node are known.
+func missingInterfaces(entries []string, ifaces []pfstatusrelayv1alpha1.HostInterface, known bool) []string {
+	names := sets.New[string]()
+	for _, iface := range ifaces {
+		names.Insert(iface.Name)
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		selector, err := pfstatusrelayv1alpha1.ParseInterfaceSelector(entry)
+		if err != nil {
+			missing = append(missing, entry)
+			continue
+		}
+
+		if selector == nil {
+			if known && !names.Has(entry) {
+				missing = append(missing, entry)
+			}
+			continue
+		}
+
+		found := false
+		for _, iface := range ifaces {
+			if selector.Matches(iface) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, entry)
+		}
+	}
+
+	return missing
+}
+
+// setMissingInterfaces records the interfaces not found on the selected nodes as a status condition.
+func setMissingInterfaces(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, missing map[string][]string) {
+	if len(missing) == 0 {
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionMissingInterfaces, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonAllInterfacesFound, "")
+		return
+	}
+
+	nodes := make([]string, 0, len(missing))
+	for node := range missing {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	details := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		details = append(details, fmt.Sprintf("%s %s", node, missing[node]))
+	}
+
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionMissingInterfaces, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonInterfacesNotFound,
+		fmt.Sprintf("interfaces not found on nodes: %s", strings.Join(details, ", ")))
+}
+
+// syncInterfaceStatus reports the LACP and VF link state of the interfaces the monitor monitors on each
+// selected node, as found by the node agent. The transition time of an interface is kept while its state
+// does not change.
+func syncInterfaceStatus(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, nodeList *corev1.NodeList, inventory map[string][]pfstatusrelayv1alpha1.PFStatus) error {
+	previous := make(map[string]pfstatusrelayv1alpha1.InterfaceStatus, len(pfMonitor.Status.Interfaces))
+	for _, status := range pfMonitor.Status.Interfaces {
+		previous[status.NodeName+"/"+status.Name] = status
+	}
+
+	now := metav1.Now()
+	var statuses []pfstatusrelayv1alpha1.InterfaceStatus
+	for _, node := range nodeList.Items {
+		entries, _, err := pfstatusrelayv1alpha1.NodeInterfaces(pfMonitor, &node)
+		if err != nil {
+			return err
+		}
+
+		pfs, known := inventory[node.Name]
+		if entries == nil || !known {
+			continue
+		}
+
+		names, err := pfstatusrelayv1alpha1.ResolveInterfaces(entries, discovery.HostInterfaces(pfs))
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			for _, pf := range pfs {
+				if pf.Name != name {
+					continue
+				}
+
+				status := pfstatusrelayv1alpha1.InterfaceStatus{
+					NodeName:           node.Name,
+					Name:               name,
+					LACP:               lacpState(pf.LACP),
+					VFLinkState:        pf.VFLinkState,
+					LastTransitionTime: now,
+				}
+				if old, ok := previous[node.Name+"/"+name]; ok && old.LACP == status.LACP && old.VFLinkState == status.VFLinkState {
+					status.LastTransitionTime = old.LastTransitionTime
+				}
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].NodeName != statuses[j].NodeName {
+			return statuses[i].NodeName < statuses[j].NodeName
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	pfMonitor.Status.Interfaces = statuses
+
+	return nil
+}
+
+// lacpState returns the LACP state reported for a PF.
+func lacpState(lacp *pfstatusrelayv1alpha1.LACPStatus) string {
+	switch {
+	case lacp == nil:
+		return pfstatusrelayv1alpha1.LACPStateUnknown
+	case lacp.Synchronized:
+		return pfstatusrelayv1alpha1.LACPStateUp
+	default:
+		return pfstatusrelayv1alpha1.LACPStateDown
+	}
+}
+
+// nodeNameAffinity returns a required node affinity matching the named nodes.
+func nodeNameAffinity(nodes []string) *corev1.Affinity {
+	return nodeAffinity([]corev1.NodeSelectorTerm{{
+		MatchFields: []corev1.NodeSelectorRequirement{{
+			Key:      metav1.ObjectNameField,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   nodes,
+		}},
+	}})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+)
+
+// syncNetworkPolicy server-side applies the NetworkPolicy of the monitor relay pods.
+//
+// The relay only talks to the kernel through netlink: it neither serves metrics nor
+// reaches the API server, so the policy declares that the relay pods need no ingress
+// nor egress pod traffic. The relay pods run on the host network, which network plugins
+// usually exclude from policies, so the policy documents the traffic of the relay rather
+// than isolating it. It is applied whatever the state of the monitor, and is owned by the
+// monitor and garbage collected together with its DaemonSets.
+func (r *PFLACPMonitorReconciler) syncNetworkPolicy(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) error {
+	name := fmt.Sprintf("%s-np-%s", namePrefix, pfMonitor.Name)
+
+	refNp := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pfMonitor.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					monitorLabel: pfMonitor.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(pfMonitor, refNp, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	refNp.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+	npApply := &networkingv1ac.NetworkPolicyApplyConfiguration{}
+	if err := toApplyConfiguration(refNp, npApply); err != nil {
+		return fmt.Errorf("failed to convert network policy: %w", err)
+	}
+
+	np := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pfMonitor.Namespace}, np)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get network policy: %w", err)
+	}
+	if err == nil {
+		if err = upgradeManagedFields(ctx, r.Client, np); err != nil {
+			return err
+		}
+
+		owned, err := networkingv1ac.ExtractNetworkPolicy(np, fieldManager)
+		if err != nil {
+			return fmt.Errorf("failed to extract network policy fields: %w", err)
+		}
+		if equality.Semantic.DeepEqual(owned, npApply) {
+			log.FromContext(ctx).Debug("network policy already up to date", "networkPolicy", name)
+			return nil
+		}
+		log.FromContext(ctx).Info("network policy found, applying", "networkPolicy", name)
+	} else {
+		log.FromContext(ctx).Info("network policy not found, applying", "networkPolicy", name)
+	}
+
+	if err = r.Apply(ctx, npApply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply network policy: %w", err)
+	}
+
+	return nil
+}
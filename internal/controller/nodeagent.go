@@ -0,0 +1,164 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+)
+
+const (
+	nodeAgentName = "pf-status-relay-node-agent"
+
+	nodeAgentSAName = "pf-status-relay-operator-pf-status-relay-node-agent"
+
+	// nodeAgentImageEnv is the environment variable holding the image of the node agent, which is the operator image,
+	// when the operator config sets none.
+	nodeAgentImageEnv = "PF_STATUS_RELAY_NODE_AGENT_IMAGE"
+)
+
+// syncNodeAgent server-side applies the node agent DaemonSet with the image of the operator config. The DaemonSet
+// is not owned by the operator config, which may not exist, so it is labeled as managed by the operator instead.
+func (r *OperatorConfigReconciler) syncNodeAgent(ctx context.Context, config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig) error {
+	image := nodeAgentImage(config)
+	if image == "" {
+		log.FromContext(ctx).Info("node agent image not configured, PFLACPNodeStates will not be reported", "env", nodeAgentImageEnv)
+		return nil
+	}
+
+	refDs := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeAgentName,
+			Namespace: r.Namespace,
+			Labels: map[string]string{
+				managedByLabel: fieldManager,
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": nodeAgentName,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": nodeAgentName,
+					},
+				},
+				Spec: corev1.PodSpec{
+					// The interfaces of the node are only visible from its network namespace
+					HostNetwork:        true,
+					ServiceAccountName: nodeAgentSAName,
+					ImagePullSecrets:   config.Spec.ImagePullSecrets,
+					NodeSelector: map[string]string{
+						corev1.LabelOSStable: "linux",
+					},
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            nodeAgentName,
+							Image:           image,
+							ImagePullPolicy: config.Spec.ImagePullPolicy,
+							Command:         nodeAgentCommand(config),
+							Env: []corev1.EnvVar{
+								{
+									Name: "NODE_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "spec.nodeName",
+										},
+									},
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+									corev1.ResourceMemory: resource.MustParse("32Mi"),
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: func(b bool) *bool { return &b }(false),
+								ReadOnlyRootFilesystem:   func(b bool) *bool { return &b }(true),
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{"ALL"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dsApply, err := daemonSetApplyConfiguration(refDs)
+	if err != nil {
+		return err
+	}
+
+	ds := &appsv1.DaemonSet{}
+	err = r.Get(ctx, client.ObjectKeyFromObject(refDs), ds)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to get node agent daemonset: %w", err)
+	}
+	if err == nil {
+		if err = upgradeManagedFields(ctx, r.Client, ds); err != nil {
+			return err
+		}
+
+		// Only the fields owned by the operator are compared, like for the relay DaemonSets
+		owned, err := appsv1ac.ExtractDaemonSet(ds, fieldManager)
+		if err != nil {
+			return fmt.Errorf("failed to extract node agent daemonset fields: %w", err)
+		}
+		if equality.Semantic.DeepEqual(owned, dsApply) {
+			return nil
+		}
+	}
+
+	log.FromContext(ctx).Info("applying node agent daemonset", "daemonSet", client.ObjectKeyFromObject(refDs).String())
+	if err = r.Apply(ctx, dsApply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply node agent daemonset: %w", err)
+	}
+
+	return nil
+}
+
+// nodeAgentCommand returns the command of the node agent, with the log level and format of the operator config.
+func nodeAgentCommand(config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig) []string {
+	command := []string{"/manager", "--node-agent"}
+	if config.Spec.LogLevel != "" {
+		command = append(command, "--log-level="+config.Spec.LogLevel)
+	}
+	if config.Spec.LogFormat != "" {
+		command = append(command, "--log-format="+config.Spec.LogFormat)
+	}
+	return command
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+	"github.com/openshift/pf-status-relay-operator/internal/tlsprofile"
+)
+
+// relayImageEnv is the environment variable holding the relay image when the operator config sets none.
+const relayImageEnv = "PF_STATUS_RELAY_IMAGE"
+
+// OperatorConfigReconciler applies the PFStatusRelayOperatorConfig singleton to the operator: it sets the log
+// level and format and the TLS profile, and deploys the node agent DaemonSet, which discovers the PFs of every node and reports
+// them in PFLACPNodeStates. The relay DaemonSets are updated by the PFLACPMonitorReconciler.
+type OperatorConfigReconciler struct {
+	client.Client
+
+	// Namespace is the namespace of the operator, where the node agent is deployed
+	Namespace string
+
+	// TLSProfile is the TLS profile of the servers of the operator, set from the operator config on clusters
+	// without the config.openshift.io API. It is nil on OpenShift, where the APIServer profile is used
+	TLSProfile *tlsprofile.Reloader
+
+	// FallbackTLSProfile is the TLS profile used when the operator config sets none
+	FallbackTLSProfile *configv1.TLSSecurityProfile
+}
+
+// +kubebuilder:rbac:groups=pfstatusrelay.openshift.io,resources=pfstatusrelayoperatorconfigs,verbs=get;list;watch
+
+// Reconcile applies the operator config, or the defaults of the operator when it does not exist.
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("reconciling PFStatusRelayOperatorConfig")
+
+	config, err := getOperatorConfig(ctx, r)
+	if err != nil {
+		logger.Error("unable to get PFStatusRelayOperatorConfig", "error", err)
+		return ctrl.Result{}, err
+	}
+
+	if err = log.SetLevel(config.Spec.LogLevel); err != nil {
+		logger.Error("invalid log level", "level", config.Spec.LogLevel, "error", err)
+	}
+	if err = log.SetFormat(config.Spec.LogFormat); err != nil {
+		logger.Error("invalid log format", "format", config.Spec.LogFormat, "error", err)
+	}
+
+	if r.TLSProfile != nil {
+		spec, err := tlsprofile.FallbackProfileSpec(config, r.FallbackTLSProfile)
+		if err != nil {
+			logger.Error("invalid TLS profile", "error", err)
+		} else {
+			r.TLSProfile.Set(spec)
+		}
+	}
+
+	if err = r.syncNodeAgent(ctx, config); err != nil {
+		logger.Error("failed to sync node agent daemonset", "error", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The operator is configured once started, even when no operator config exists
+	start := make(chan event.GenericEvent, 1)
+	start <- event.GenericEvent{Object: &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: pfstatusrelayv1alpha1.OperatorConfigName},
+	}}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("pfstatusrelayoperatorconfig").
+		For(&pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{}, builder.WithPredicates(operatorConfigPredicate())).
+		WatchesRawSource(source.Channel(start, &handler.EnqueueRequestForObject{})).
+		// The node agent DaemonSet is restored when it is deleted or its spec is changed
+		Watches(&appsv1.DaemonSet{}, handler.EnqueueRequestsFromMapFunc(operatorConfigRequest),
+			builder.WithPredicates(r.nodeAgentPredicate(), predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// nodeAgentPredicate filters the events of the DaemonSets other than the node agent one.
+func (r *OperatorConfigReconciler) nodeAgentPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == r.Namespace && obj.GetName() == nodeAgentName
+	})
+}
+
+// operatorConfigRequest returns the request of the operator config singleton.
+func operatorConfigRequest(_ context.Context, _ client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pfstatusrelayv1alpha1.OperatorConfigName}}}
+}
+
+// operatorConfigPredicate filters the events of the operator configs other than the singleton.
+func operatorConfigPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == pfstatusrelayv1alpha1.OperatorConfigName
+	})
+}
+
+// getOperatorConfig returns the operator config, or an empty one when it does not exist.
+func getOperatorConfig(ctx context.Context, c client.Reader) (*pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig, error) {
+	config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{}
+	err := c.Get(ctx, client.ObjectKey{Name: pfstatusrelayv1alpha1.OperatorConfigName}, config)
+	if client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// relayImage returns the relay image of the operator config, falling back to the PF_STATUS_RELAY_IMAGE
+// environment variable.
+func relayImage(config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig) (string, error) {
+	if config.Spec.RelayImage != "" {
+		return config.Spec.RelayImage, nil
+	}
+
+	image, found := os.LookupEnv(relayImageEnv)
+	if !found || image == "" {
+		return "", fmt.Errorf("%w: set spec.relayImage of the %s PFStatusRelayOperatorConfig or %s",
+			errImageNotConfigured, pfstatusrelayv1alpha1.OperatorConfigName, relayImageEnv)
+	}
+	return image, nil
+}
+
+// nodeAgentImage returns the node agent image of the operator config, falling back to the
+// PF_STATUS_RELAY_NODE_AGENT_IMAGE environment variable. It is empty when neither is set.
+func nodeAgentImage(config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig) string {
+	if config.Spec.NodeAgentImage != "" {
+		return config.Spec.NodeAgentImage
+	}
+
+	return os.Getenv(nodeAgentImageEnv)
+}
+
+// relayPodTemplate returns the relay pod settings of the monitor, completed with the defaults of the operator
+// config. The labels and annotations are merged, the monitor taking precedence.
+func relayPodTemplate(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig) *pfstatusrelayv1alpha1.RelayPodTemplate {
+	relayPod := &pfstatusrelayv1alpha1.RelayPodTemplate{}
+	if pfMonitor.Spec.RelayPod != nil {
+		relayPod = pfMonitor.Spec.RelayPod.DeepCopy()
+	}
+
+	defaults := config.Spec.RelayPodDefaults
+	if defaults == nil {
+		return relayPod
+	}
+	defaults = defaults.DeepCopy()
+
+	relayPod.Labels = mergeMaps(defaults.Labels, relayPod.Labels)
+	relayPod.Annotations = mergeMaps(defaults.Annotations, relayPod.Annotations)
+	if relayPod.Tolerations == nil {
+		relayPod.Tolerations = defaults.Tolerations
+	}
+	if relayPod.Affinity == nil {
+		relayPod.Affinity = defaults.Affinity
+	}
+	if relayPod.PriorityClassName == "" {
+		relayPod.PriorityClassName = defaults.PriorityClassName
+	}
+	if relayPod.Resources == nil {
+		relayPod.Resources = defaults.Resources
+	}
+
+	return relayPod
+}
+
+// mergeMaps returns the entries of both maps, the entries of override taking precedence. It returns nil when
+// both are empty.
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
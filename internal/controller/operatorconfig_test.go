@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+var _ = Describe("PFStatusRelayOperatorConfig Controller", func() {
+	Context("When the node agent image is configured", func() {
+		const (
+			timeout  = time.Second * 10
+			interval = time.Millisecond * 250
+
+			nodeAgentImage = "quay.io/openshift/origin-pf-status-relay-operator@sha256:" +
+				"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+			editedImage = "quay.io/openshift/origin-pf-status-relay-operator:edited"
+		)
+
+		ctx := context.Background()
+		dsKey := types.NamespacedName{Name: nodeAgentName, Namespace: "default"}
+
+		BeforeEach(func() {
+			By("creating the operator config with the node agent image")
+			config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: pfstatusrelayv1alpha1.OperatorConfigName},
+				Spec: pfstatusrelayv1alpha1.PFStatusRelayOperatorConfigSpec{
+					NodeAgentImage: nodeAgentImage,
+				},
+			}
+			Expect(k8sClient.Create(ctx, config)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("deleting the operator config")
+			config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: pfstatusrelayv1alpha1.OperatorConfigName},
+			}
+			Expect(k8sClient.Delete(ctx, config)).To(Succeed())
+		})
+
+		It("should only revert the fields of the node agent DaemonSet owned by the operator", func() {
+			ds := &appsv1.DaemonSet{}
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsKey, ds)).To(Succeed())
+				g.Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(1))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal(nodeAgentImage))
+				g.Expect(ds.Labels).To(HaveKeyWithValue(managedByLabel, fieldManager))
+			}, timeout, interval).Should(Succeed())
+
+			By("reconciling the operator config without changes")
+			resourceVersion := ds.ResourceVersion
+			config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: pfstatusrelayv1alpha1.OperatorConfigName}, config)).To(Succeed())
+			config.Annotations = map[string]string{"test": "resync"}
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsKey, ds)).To(Succeed())
+				g.Expect(ds.ResourceVersion).To(Equal(resourceVersion))
+			}, time.Second*2, interval).Should(Succeed())
+
+			By("editing the image and a field not owned by the operator")
+			ds.Spec.Template.Spec.Containers[0].Image = editedImage
+			ds.Spec.MinReadySeconds = 10
+			Expect(k8sClient.Update(ctx, ds, client.FieldOwner("test-editor"))).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, dsKey, ds)).To(Succeed())
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal(nodeAgentImage))
+			}, timeout, interval).Should(Succeed())
+			Expect(ds.Spec.MinReadySeconds).To(BeEquivalentTo(10))
+
+			By("deleting the node agent DaemonSet")
+			Expect(k8sClient.Delete(ctx, ds)).To(Succeed())
+			Eventually(func(g Gomega) {
+				restored := &appsv1.DaemonSet{}
+				g.Expect(k8sClient.Get(ctx, dsKey, restored)).To(Succeed())
+				g.Expect(restored.UID).NotTo(Equal(ds.UID))
+			}, timeout, interval).Should(Succeed())
+		})
+	})
+})
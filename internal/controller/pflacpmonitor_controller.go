@@ -18,34 +18,72 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/csaupgrade"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
 	"github.com/openshift/pf-status-relay-operator/internal/log"
+	"github.com/openshift/pf-status-relay-operator/internal/metrics"
 )
 
 const (
 	pfStatusRelaySAName = "pf-status-relay-operator-pf-status-relay"
 
-	namePrefix = "pf-status-relay"
+	namePrefix = pfstatusrelayv1alpha1.RelayNamePrefix
+
+	relayContainerName = "pf-status-relay"
+
+	// fieldManager is the field manager of the resources applied by the operator.
+	fieldManager = "pf-status-relay-operator"
+
+	// managedByLabel is set on the resources deployed by the operator that are not owned by a monitor.
+	managedByLabel = "app.kubernetes.io/managed-by"
+
+	// defaultRelayPriorityClassName is the priority class of the relay pods when the monitor sets none.
+	defaultRelayPriorityClassName = "system-node-critical"
+
+	// monitorLabel is set on the resources deployed for a monitor to the monitor name.
+	monitorLabel = "pfstatusrelay.openshift.io/monitor"
 )
 
+var errImageNotConfigured = errors.New("pf-status-relay image not configured")
+
+// legacyFieldManager is the field manager of the DaemonSets created and updated by the operator before they were
+// applied, which the API server derives from the default user agent of the operator, such as manager.
+var legacyFieldManager, _, _ = strings.Cut(rest.DefaultKubernetesUserAgent(), "/")
+
 // PFLACPMonitorReconciler reconciles a PFLACPMonitor object
 type PFLACPMonitorReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=pfstatusrelay.openshift.io,resources=pflacpmonitors,verbs=get;list;watch;create;update;patch;delete,namespace=system
@@ -53,91 +91,343 @@ type PFLACPMonitorReconciler struct {
 // +kubebuilder:rbac:groups=pfstatusrelay.openshift.io,resources=pflacpmonitors/finalizers,verbs=update,namespace=system
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete,namespace=system
 // +kubebuilder:rbac:groups=apps,resources=daemonsets/status,verbs=get,namespace=system
-// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;update;patch,namespace=system
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch,namespace=system
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete,namespace=system
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch,namespace=system
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=pfstatusrelay.openshift.io,resources=pflacpnodestates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=pfstatusrelay.openshift.io,resources=pfinterfaceclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;delete;update;patch,namespace=system
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *PFLACPMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log.Log.Info("reconciling PFLACPMonitor", "name", req.Name, "namespace", req.Namespace)
+	logger := log.FromContext(ctx)
+	logger.Info("reconciling PFLACPMonitor")
 
 	pfMonitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
 	err := r.Get(ctx, req.NamespacedName, pfMonitor)
 	if err != nil {
 		if client.IgnoreNotFound(err) == nil {
-			log.Log.Debug("PFLACPMonitor not found, ignoring")
+			logger.Debug("PFLACPMonitor not found, ignoring")
+			metrics.DeleteMonitor(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
-		log.Log.Error("unable to get PFLACPMonitor", "error", err)
+		logger.Error("unable to get PFLACPMonitor", "error", err)
+		return ctrl.Result{}, err
+	}
+
+	if !pfMonitor.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalizeMonitor(ctx, pfMonitor)
+	}
+
+	if controllerutil.AddFinalizer(pfMonitor, interfaceClaimsFinalizer) {
+		if err = r.Update(ctx, pfMonitor); err != nil {
+			logger.Error("failed to add finalizer", "error", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	oldStatus := pfMonitor.Status.DeepCopy()
+	result, err := r.reconcileMonitor(ctx, pfMonitor)
+
+	if statusErr := r.updateStatus(ctx, pfMonitor, oldStatus); statusErr != nil {
+		logger.Error("failed to update status", "error", statusErr)
+		if err == nil {
+			err = statusErr
+		}
+	}
+	recordMonitorMetrics(pfMonitor, err)
+
+	return result, err
+}
+
+// finalizeMonitor releases the interfaces claimed by a deleted monitor and removes its finalizer.
+// Its DaemonSets and NetworkPolicy are garbage collected.
+func (r *PFLACPMonitorReconciler) finalizeMonitor(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) error {
+	if !controllerutil.ContainsFinalizer(pfMonitor, interfaceClaimsFinalizer) {
+		return nil
+	}
+
+	if err := r.releaseInterfaces(ctx, pfMonitor); err != nil {
+		log.FromContext(ctx).Error("failed to release interface claims", "error", err)
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(pfMonitor, interfaceClaimsFinalizer)
+	if err := r.Update(ctx, pfMonitor); err != nil {
+		log.FromContext(ctx).Error("failed to remove finalizer", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// reconcileMonitor drives the DaemonSet towards the monitor spec and records the outcome as status conditions.
+func (r *PFLACPMonitorReconciler) reconcileMonitor(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// The NetworkPolicy does not depend on the interfaces, and is kept in place while the monitor is in conflict
+	err := r.syncNetworkPolicy(ctx, pfMonitor)
+	if err != nil {
+		logger.Error("failed to sync network policy", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonNetworkPolicySyncFailed, err)
 		return ctrl.Result{}, err
 	}
 
 	pfMonitorList := &pfstatusrelayv1alpha1.PFLACPMonitorList{}
 	err = r.List(ctx, pfMonitorList)
 	if err != nil {
-		log.Log.Error("unable to list PFLACPMonitor", "error", err)
+		logger.Error("unable to list PFLACPMonitor", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonListFailed, err)
 		return ctrl.Result{}, err
 	}
 
-	err = pfstatusrelayv1alpha1.InterfaceUniqueness(pfMonitor, pfMonitorList)
+	nodeList := &corev1.NodeList{}
+	err = r.List(ctx, nodeList)
 	if err != nil {
-		if pfMonitor.Status.Degraded {
-			return ctrl.Result{}, nil
+		logger.Error("unable to list nodes", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonListFailed, err)
+		return ctrl.Result{}, err
+	}
+
+	inventory, err := r.getInventory(ctx)
+	if err != nil {
+		logger.Error("unable to list PFLACPNodeState", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonListFailed, err)
+		return ctrl.Result{}, err
+	}
+
+	conflicted := meta.IsStatusConditionTrue(pfMonitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict)
+	err = pfstatusrelayv1alpha1.MonitorConflict(pfMonitor, pfMonitorList, nodeList)
+	if err == nil {
+		// Monitors of other namespaces are only visible through the interface claims
+		err = r.claimInterfaces(ctx, pfMonitor, nodeList, inventory)
+		var claimConflict *pfstatusrelayv1alpha1.InterfaceClaimConflict
+		if err != nil && !errors.As(err, &claimConflict) {
+			logger.Error("failed to claim interfaces", "error", err)
+			setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonInterfaceClaimFailed, err)
+			return ctrl.Result{}, err
+		}
+	}
+	if err != nil {
+		logger.Error("failed to validate PFLACPMonitor", "error", err)
+		if !conflicted {
+			r.Recorder.Event(pfMonitor, corev1.EventTypeWarning, pfstatusrelayv1alpha1.EventReasonInterfaceConflict, err.Error())
 		}
 
-		log.Log.Error("failed to validate PFLACPMonitor", "error", err)
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionInterfaceConflict, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonInterfacesInUse, err.Error())
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionAvailable, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonInterfacesInUse, err.Error())
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonInterfacesInUse, err.Error())
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionDegraded, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonInterfacesInUse, err.Error())
 
-		pfMonitor.Status.Degraded = true
-		pfMonitor.Status.ErrorMessage = err.Error()
+		clearRolloutStatus(pfMonitor)
+		pfMonitor.Status.Interfaces = nil
+		pfMonitor.Status.WinningMonitor = winningMonitor(err)
 
-		if err = r.Status().Update(ctx, pfMonitor); err != nil {
-			log.Log.Error("failed to update status", "error", err)
+		// Delete daemonsets if exist
+		err = r.deleteDaemonSets(ctx, pfMonitor, nil)
+		if err != nil {
+			logger.Error("failed to delete daemonset", "error", err)
+			setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonDaemonSetDeleteFailed, err)
 			return ctrl.Result{}, err
 		}
 
-		// Delete daemonset if exists
-		err = r.deleteDaemonSet(ctx, pfMonitor)
+		err = r.releaseInterfaces(ctx, pfMonitor)
 		if err != nil {
-			log.Log.Error("failed to delete daemonset", "error", err)
+			logger.Error("failed to release interface claims", "error", err)
+			setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonInterfaceClaimFailed, err)
 			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{}, nil
 	}
 
-	err = r.syncDaemonSet(ctx, pfMonitor)
+	if conflicted {
+		r.Recorder.Event(pfMonitor, corev1.EventTypeNormal, pfstatusrelayv1alpha1.EventReasonConflictResolved, "Interfaces no longer in use by another PFLACPMonitor")
+	}
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionInterfaceConflict, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonNoConflict, "")
+	pfMonitor.Status.WinningMonitor = ""
+
+	groups, missing, err := resolveRelayGroups(pfMonitor, relayGroups(pfMonitor), nodeList, inventory)
 	if err != nil {
-		log.Log.Error("failed to sync daemonset", "error", err)
+		logger.Error("failed to resolve interfaces", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonInterfacesUnresolved, err)
 		return ctrl.Result{}, err
 	}
+	if len(missing) > 0 {
+		logger.Info("interfaces not found on nodes", "missing", missing)
+	}
+	setMissingInterfaces(pfMonitor, missing)
 
-	if pfMonitor.Status.Degraded {
-		pfMonitor.Status.Degraded = false
-		pfMonitor.Status.ErrorMessage = ""
+	err = syncInterfaceStatus(pfMonitor, nodeList, inventory)
+	if err != nil {
+		logger.Error("failed to sync interface status", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonInterfacesUnresolved, err)
+		return ctrl.Result{}, err
+	}
 
-		if err = r.Status().Update(ctx, pfMonitor); err != nil {
-			log.Log.Error("failed to update status", "error", err)
+	logLevel, overrideLeft := relayLogLevel(pfMonitor, time.Now())
+
+	daemonSets := make([]*appsv1.DaemonSet, 0, len(groups))
+	keep := sets.New[string]()
+	held := false
+	for _, group := range groups {
+		ds, dsHeld, err := r.syncDaemonSet(ctx, pfMonitor, group, logLevel)
+		if err != nil {
+			logger.Error("failed to sync daemonset", "error", err)
+			reason := pfstatusrelayv1alpha1.ReasonDaemonSetSyncFailed
+			if errors.Is(err, errImageNotConfigured) {
+				reason = pfstatusrelayv1alpha1.ReasonImageNotConfigured
+				r.Recorder.Event(pfMonitor, corev1.EventTypeWarning, pfstatusrelayv1alpha1.EventReasonImageNotConfigured, err.Error())
+			}
+			setDegraded(pfMonitor, reason, err)
 			return ctrl.Result{}, err
 		}
+		daemonSets = append(daemonSets, ds)
+		keep.Insert(ds.Name)
+		held = held || dsHeld
+	}
+
+	// Delete the daemonsets of removed overrides
+	err = r.deleteDaemonSets(ctx, pfMonitor, keep)
+	if err != nil {
+		logger.Error("failed to delete daemonset", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonDaemonSetDeleteFailed, err)
+		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	var canaryStatus *pfstatusrelayv1alpha1.RolloutStatus
+	var requeueAfter time.Duration
+	if canaryRollout(pfMonitor) {
+		canaryStatus, requeueAfter, err = r.rolloutCanary(ctx, pfMonitor, daemonSets, nodeList)
+		if err != nil {
+			logger.Error("failed to roll out relay pods", "error", err)
+			setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonDaemonSetSyncFailed, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = r.syncRolloutStatus(ctx, pfMonitor, daemonSets, held, canaryStatus)
+	if err != nil {
+		logger.Error("failed to sync rollout status", "error", err)
+		setDegraded(pfMonitor, pfstatusrelayv1alpha1.ReasonDaemonSetSyncFailed, err)
+		return ctrl.Result{}, err
+	}
+
+	// The relay pods are rolled out again once the log level override expires
+	if overrideLeft > 0 && (requeueAfter == 0 || overrideLeft < requeueAfter) {
+		requeueAfter = overrideLeft
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// winningMonitor returns the monitor, as namespace/name, that a monitor lost its interfaces to because of err.
+func winningMonitor(err error) string {
+	var conflict *pfstatusrelayv1alpha1.InterfaceConflictError
+	if errors.As(err, &conflict) {
+		return conflict.Namespace + "/" + conflict.Name
+	}
+
+	var claimConflict *pfstatusrelayv1alpha1.InterfaceClaimConflict
+	if errors.As(err, &claimConflict) {
+		return claimConflict.Monitor
+	}
+
+	return ""
+}
+
+// updateStatus writes the monitor status if it differs from oldStatus.
+func (r *PFLACPMonitorReconciler) updateStatus(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, oldStatus *pfstatusrelayv1alpha1.PFLACPMonitorStatus) error {
+	pfMonitor.Status.ObservedGeneration = pfMonitor.Generation
+	if equality.Semantic.DeepEqual(oldStatus, &pfMonitor.Status) {
+		return nil
+	}
+
+	return r.Status().Update(ctx, pfMonitor)
+}
+
+// setCondition sets a status condition on the monitor for its current generation.
+func setCondition(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&pfMonitor.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pfMonitor.Generation,
+	})
+}
+
+// recordMonitorMetrics records the state of the monitor and its relay pods, and the time of the reconcile when it
+// succeeded.
+func recordMonitorMetrics(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, err error) {
+	metrics.SetMonitor(pfMonitor.Namespace, pfMonitor.Name, monitorState(pfMonitor),
+		pfMonitor.Status.DesiredNumberScheduled, pfMonitor.Status.NumberReady)
+	if err == nil {
+		metrics.SetReconcileSuccess(pfMonitor.Namespace, pfMonitor.Name, time.Now())
+	}
+}
+
+// monitorState returns the state of the monitor reported in the metrics, from its conditions.
+func monitorState(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) string {
+	switch {
+	case meta.IsStatusConditionTrue(pfMonitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict):
+		return metrics.MonitorStateConflict
+	case meta.IsStatusConditionTrue(pfMonitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionDegraded):
+		return metrics.MonitorStateDegraded
+	}
+	return metrics.MonitorStateHealthy
+}
+
+// setDegraded marks the monitor as Degraded and not Available because of err.
+func setDegraded(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, reason string, err error) {
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionDegraded, metav1.ConditionTrue, reason, err.Error())
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionAvailable, metav1.ConditionFalse, reason, err.Error())
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionFalse, reason, err.Error())
 }
 
-func (r *PFLACPMonitorReconciler) syncDaemonSet(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) error {
-	log.Log.Info("syncing daemonset", "name", pfMonitor.Name, "namespace", pfMonitor.Namespace)
+// syncDaemonSet server-side applies the DaemonSet of a relay group of the monitor and returns it. The apply is
+// skipped when the fields owned by the operator already hold the expected values. While the rollout of the monitor
+// is paused, the changes to an existing DaemonSet are held, which is reported by returning true.
+func (r *PFLACPMonitorReconciler) syncDaemonSet(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, group relayGroup, logLevel string) (*appsv1.DaemonSet, bool, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("syncing daemonset")
 
-	name := fmt.Sprintf("%s-ds-%s", namePrefix, pfMonitor.Name)
-	image, err := getDaemonSetImage()
+	name := group.name
+	config, err := getOperatorConfig(ctx, r)
 	if err != nil {
-		return err
+		return nil, false, fmt.Errorf("failed to get operator config: %w", err)
+	}
+	image, err := relayImage(config)
+	if err != nil {
+		return nil, false, err
+	}
+
+	relayPod := relayPodTemplate(pfMonitor, config)
+
+	podLabels := make(map[string]string, len(relayPod.Labels)+2)
+	for key, value := range relayPod.Labels {
+		podLabels[key] = value
+	}
+	podLabels["app"] = name
+	podLabels[monitorLabel] = pfMonitor.Name
+
+	priorityClassName := relayPod.PriorityClassName
+	if priorityClassName == "" {
+		priorityClassName = defaultRelayPriorityClassName
 	}
 
 	refDs := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: pfMonitor.Namespace,
+			Labels: map[string]string{
+				monitorLabel: pfMonitor.Name,
+			},
 		},
 		Spec: appsv1.DaemonSetSpec{
+			UpdateStrategy: daemonSetUpdateStrategy(pfMonitor.Spec.Rollout),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app": name,
@@ -145,25 +435,29 @@ func (r *PFLACPMonitorReconciler) syncDaemonSet(ctx context.Context, pfMonitor *
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app": name,
-					},
+					Labels:      podLabels,
+					Annotations: relayPod.Annotations,
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: pfStatusRelaySAName,
 					HostNetwork:        true,
 					NodeSelector:       pfMonitor.Spec.NodeSelector,
+					Affinity:           relayAffinity(group.affinity, relayPod.Affinity),
+					Tolerations:        relayPod.Tolerations,
+					PriorityClassName:  priorityClassName,
+					ImagePullSecrets:   config.Spec.ImagePullSecrets,
 					Containers: []corev1.Container{
 						{
-							Name:  "pf-status-relay",
-							Image: image,
+							Name:            relayContainerName,
+							Image:           image,
+							ImagePullPolicy: config.Spec.ImagePullPolicy,
 							SecurityContext: &corev1.SecurityContext{
 								Privileged: func(b bool) *bool { return &b }(true),
 							},
 							Env: []corev1.EnvVar{
 								{
 									Name:  "PF_STATUS_RELAY_INTERFACES",
-									Value: strings.Join(pfMonitor.Spec.Interfaces, ","),
+									Value: strings.Join(group.interfaces, ","),
 								},
 								{
 									Name:  "PF_STATUS_RELAY_POLLING_INTERVAL",
@@ -177,53 +471,190 @@ func (r *PFLACPMonitorReconciler) syncDaemonSet(ctx context.Context, pfMonitor *
 		},
 	}
 
+	if relayPod.Resources != nil {
+		refDs.Spec.Template.Spec.Containers[0].Resources = *relayPod.Resources
+	}
+	if logLevel != "" {
+		container := &refDs.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "PF_STATUS_RELAY_LOG_LEVEL",
+			Value: logLevel,
+		})
+	}
+
+	if err = controllerutil.SetControllerReference(pfMonitor, refDs, r.Scheme); err != nil {
+		return nil, false, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	dsApply, err := daemonSetApplyConfiguration(refDs)
+	if err != nil {
+		return nil, false, err
+	}
+
 	ds := &appsv1.DaemonSet{}
 	err = r.Get(ctx, types.NamespacedName{Name: name, Namespace: pfMonitor.Namespace}, ds)
-	if err != nil {
-		if client.IgnoreNotFound(err) == nil {
-			log.Log.Info("daemon set not found, creating", "name", name)
+	if client.IgnoreNotFound(err) != nil {
+		return nil, false, fmt.Errorf("failed to get daemon set: %w", err)
+	}
+	found := err == nil
 
-			if err = controllerutil.SetControllerReference(pfMonitor, refDs, r.Scheme); err != nil {
-				return fmt.Errorf("failed to set controller reference: %w", err)
-			}
+	if found {
+		// A DaemonSet of the same name deployed for another monitor is not taken over
+		if owner := metav1.GetControllerOf(ds); owner != nil && owner.UID != pfMonitor.UID {
+			return nil, false, fmt.Errorf("daemon set %s is owned by %s %s", name, owner.Kind, owner.Name)
+		}
 
-			if err = r.Create(ctx, refDs); err != nil {
-				return fmt.Errorf("failed to create daemon set: %w", err)
-			}
+		if err = upgradeManagedFields(ctx, r.Client, ds); err != nil {
+			return nil, false, err
+		}
 
-			return nil
+		// Only the fields owned by the operator are compared, so that the fields defaulted by the API server or
+		// set by others do not trigger an apply
+		owned, err := appsv1ac.ExtractDaemonSet(ds, fieldManager)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to extract daemon set fields: %w", err)
 		}
+		if equality.Semantic.DeepEqual(owned, dsApply) {
+			logger.Debug("daemon set already up to date", "daemonSet", name)
+			return ds, false, nil
+		}
+		if rollout := pfMonitor.Spec.Rollout; rollout != nil && rollout.Paused {
+			logger.Info("rollout paused, holding daemon set changes", "daemonSet", name)
+			return ds, true, nil
+		}
+		logger.Info("daemon set found, applying", "daemonSet", name)
+	} else {
+		logger.Info("daemon set not found, applying", "daemonSet", name)
+	}
 
-		return fmt.Errorf("failed to get daemon set: %w", err)
+	if err = r.Apply(ctx, dsApply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return nil, false, fmt.Errorf("failed to apply daemon set: %w", err)
 	}
 
-	if !equality.Semantic.DeepEqual(ds.Spec, refDs.Spec) {
-		log.Log.Info("daemon set found, updating", "name", name)
+	if found {
+		r.Recorder.Eventf(pfMonitor, corev1.EventTypeNormal, pfstatusrelayv1alpha1.EventReasonDaemonSetUpdated, "Updated DaemonSet %s", name)
+		logger.Debug("daemon set updated", "daemonSet", name)
+	} else {
+		r.Recorder.Eventf(pfMonitor, corev1.EventTypeNormal, pfstatusrelayv1alpha1.EventReasonDaemonSetCreated, "Created DaemonSet %s", name)
+	}
 
-		ds.Spec = refDs.Spec
-		if err = r.Update(ctx, ds); err != nil {
-			return fmt.Errorf("failed to update daemon set: %w", err)
-		}
+	ds, err = appliedDaemonSet(dsApply)
+	return ds, false, err
+}
 
-		log.Log.Debug("daemon set updated", "name", name)
+// upgradeManagedFields hands the fields of an object written by the operator before it was applied over to the
+// field manager of the operator, so that the fields the operator no longer sets are removed by the next apply
+// instead of being kept by the legacy field manager.
+func upgradeManagedFields(ctx context.Context, c client.Writer, obj client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(obj, sets.New(legacyFieldManager), fieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade managed fields of %s: %w", obj.GetName(), err)
+	}
+	if patch == nil {
+		return nil
 	}
 
-	log.Log.Debug("daemon set already up to date", "name", name)
+	log.FromContext(ctx).Info("upgrading managed fields", "name", obj.GetName(), "fieldManager", legacyFieldManager)
+	if err = c.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade managed fields of %s: %w", obj.GetName(), err)
+	}
 	return nil
 }
 
-func (r *PFLACPMonitorReconciler) deleteDaemonSet(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) error {
-	name := fmt.Sprintf("%s-ds-%s", namePrefix, pfMonitor.Name)
-	ds := &appsv1.DaemonSet{}
-	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pfMonitor.Namespace}, ds)
+// daemonSetApplyConfiguration returns the apply configuration asserting the fields set in ds.
+func daemonSetApplyConfiguration(ds *appsv1.DaemonSet) (*appsv1ac.DaemonSetApplyConfiguration, error) {
+	ds = ds.DeepCopy()
+	ds.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+
+	dsApply := &appsv1ac.DaemonSetApplyConfiguration{}
+	if err := toApplyConfiguration(ds, dsApply); err != nil {
+		return nil, fmt.Errorf("failed to convert daemon set: %w", err)
+	}
+
+	return dsApply, nil
+}
+
+// toApplyConfiguration converts obj into the apply configuration applyConfig, asserting the fields set in obj.
+func toApplyConfiguration(obj runtime.Object, applyConfig interface{}) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
-		if client.IgnoreNotFound(err) == nil {
-			return nil
+		return err
+	}
+	// The status is not applied with the object, and the empty structs of the typed object are not asserted
+	delete(u, "status")
+	pruneEmptyFields(u)
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u, applyConfig)
+}
+
+// pruneEmptyFields removes the null fields and the fields holding empty objects from obj, recursively.
+func pruneEmptyFields(obj map[string]interface{}) {
+	for key, value := range obj {
+		switch v := value.(type) {
+		case nil:
+			delete(obj, key)
+		case map[string]interface{}:
+			pruneEmptyFields(v)
+			if len(v) == 0 {
+				delete(obj, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneEmptyFields(m)
+				}
+			}
 		}
+	}
+}
+
+// appliedDaemonSet converts an apply configuration holding the DaemonSet returned by the API server back to a DaemonSet.
+func appliedDaemonSet(dsApply *appsv1ac.DaemonSetApplyConfiguration) (*appsv1.DaemonSet, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dsApply)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert daemon set: %w", err)
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj, ds); err != nil {
+		return nil, fmt.Errorf("failed to convert daemon set: %w", err)
+	}
+
+	return ds, nil
+}
+
+// deleteDaemonSets deletes the DaemonSets of the monitor whose name is not in keep.
+func (r *PFLACPMonitorReconciler) deleteDaemonSets(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, keep sets.Set[string]) error {
+	dsList := &appsv1.DaemonSetList{}
+	err := r.List(ctx, dsList, client.InNamespace(pfMonitor.Namespace), client.MatchingLabels{monitorLabel: pfMonitor.Name})
+	if err != nil {
 		return err
 	}
 
-	return r.Delete(ctx, ds)
+	// DaemonSets created by older versions of the operator are not labeled
+	names := sets.New(daemonSetName(pfMonitor))
+	for _, ds := range dsList.Items {
+		names.Insert(ds.Name)
+	}
+
+	for _, name := range sets.List(names.Difference(keep)) {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: pfMonitor.Namespace,
+			},
+		}
+		err = r.Delete(ctx, ds)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		r.Recorder.Eventf(pfMonitor, corev1.EventTypeNormal, pfstatusrelayv1alpha1.EventReasonDaemonSetDeleted, "Deleted DaemonSet %s", name)
+	}
+
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -231,15 +662,273 @@ func (r *PFLACPMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&pfstatusrelayv1alpha1.PFLACPMonitor{}).
 		Owns(&appsv1.DaemonSet{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(&pfstatusrelayv1alpha1.PFLACPNodeState{}, handler.EnqueueRequestsFromMapFunc(r.allMonitors)).
+		Watches(&pfstatusrelayv1alpha1.PFInterfaceClaim{}, handler.EnqueueRequestsFromMapFunc(r.allMonitors)).
+		Watches(&pfstatusrelayv1alpha1.PFInterfaceClaim{}, interfaceClaimMetrics).
+		// The changes to the operator config are rolled out to the DaemonSets of all the monitors
+		Watches(&pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{}, handler.EnqueueRequestsFromMapFunc(r.allMonitors), builder.WithPredicates(operatorConfigPredicate())).
+		// The conflicts between monitors only depend on their spec and on the node labels
+		Watches(&pfstatusrelayv1alpha1.PFLACPMonitor{}, handler.EnqueueRequestsFromMapFunc(r.peerMonitors), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeMonitors), builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Complete(r)
 }
 
-func getDaemonSetImage() (string, error) {
-	var pfStatusRelayImageEnvVar = "PF_STATUS_RELAY_IMAGE"
+// peerMonitors returns the other monitors sharing nodes or interfaces with a created, updated or deleted monitor,
+// whose conflicts with it may have changed. A monitor that lost its interfaces recovers once the winner is gone.
+func (r *PFLACPMonitorReconciler) peerMonitors(ctx context.Context, obj client.Object) []reconcile.Request {
+	pfMonitor, ok := obj.(*pfstatusrelayv1alpha1.PFLACPMonitor)
+	if !ok {
+		return nil
+	}
+
+	pfMonitorList := &pfstatusrelayv1alpha1.PFLACPMonitorList{}
+	if err := r.List(ctx, pfMonitorList); err != nil {
+		log.FromContext(ctx).Error("unable to list PFLACPMonitor", "error", err)
+		return nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		log.FromContext(ctx).Error("unable to list nodes", "error", err)
+		return nil
+	}
+
+	// A monitor with an invalid selector is reported as in conflict, so all its peers are enqueued
+	nodes, err := pfstatusrelayv1alpha1.SelectedNodes(pfMonitor, nodeList)
+	if err != nil {
+		nodes = nil
+	}
+	interfaces := monitorInterfaces(pfMonitor)
+
+	var requests []reconcile.Request
+	for _, monitor := range pfMonitorList.Items {
+		if monitor.Namespace == pfMonitor.Namespace && monitor.Name == pfMonitor.Name {
+			continue
+		}
+
+		monitorNodes, err := pfstatusrelayv1alpha1.SelectedNodes(&monitor, nodeList)
+		if err == nil && nodes != nil && !nodes.HasAny(monitorNodes.UnsortedList()...) && !interfaces.HasAny(monitorInterfaces(&monitor).UnsortedList()...) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&monitor)})
+	}
+	return requests
+}
+
+// monitorInterfaces returns the entries of the interface lists of a monitor and its overrides.
+func monitorInterfaces(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) sets.Set[string] {
+	interfaces := sets.New(pfstatusrelayv1alpha1.CanonicalInterfaces(pfMonitor.Spec.Interfaces)...)
+	for _, override := range pfMonitor.Spec.NodeOverrides {
+		interfaces.Insert(pfstatusrelayv1alpha1.CanonicalInterfaces(override.Interfaces)...)
+	}
+	return interfaces
+}
 
-	ns, found := os.LookupEnv(pfStatusRelayImageEnvVar)
-	if !found {
-		return "", fmt.Errorf("%s must be set", pfStatusRelayImageEnvVar)
+// nodeMonitors returns the monitors selecting a node that was added, removed or relabeled. Both the old and the
+// new labels of a relabeled node are mapped, so the monitors it no longer matches are reconciled too.
+func (r *PFLACPMonitorReconciler) nodeMonitors(ctx context.Context, obj client.Object) []reconcile.Request {
+	pfMonitorList := &pfstatusrelayv1alpha1.PFLACPMonitorList{}
+	if err := r.List(ctx, pfMonitorList); err != nil {
+		log.FromContext(ctx).Error("unable to list PFLACPMonitor", "error", err)
+		return nil
 	}
-	return ns, nil
+
+	var requests []reconcile.Request
+	for _, monitor := range pfMonitorList.Items {
+		selector, err := pfstatusrelayv1alpha1.NodeSelector(&monitor)
+		if err == nil && !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&monitor)})
+	}
+	return requests
+}
+
+// allMonitors returns all the monitors, to reconcile when the interfaces found on a node or the interfaces
+// claimed on it change, or when the operator config changes.
+func (r *PFLACPMonitorReconciler) allMonitors(ctx context.Context, _ client.Object) []reconcile.Request {
+	pfMonitorList := &pfstatusrelayv1alpha1.PFLACPMonitorList{}
+	if err := r.List(ctx, pfMonitorList); err != nil {
+		log.FromContext(ctx).Error("unable to list PFLACPMonitor", "error", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(pfMonitorList.Items))
+	for _, monitor := range pfMonitorList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&monitor)})
+	}
+	return requests
+}
+
+// relayGroup describes a DaemonSet relaying the same interfaces on a group of nodes of a monitor.
+type relayGroup struct {
+	name string
+	// override is the name of the node override of the group, empty for spec.interfaces
+	override   string
+	interfaces []string
+	affinity   *corev1.Affinity
+}
+
+// relayGroups returns the relay groups of the monitor: one for the nodes not matching any
+// override, followed by one per override. A node belongs to the first override it matches,
+// so each group excludes the nodes matching the overrides that take precedence over it. The
+// interfaces are canonical, as the monitors created before the defaulting webhook are not.
+func relayGroups(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) []relayGroup {
+	base := labelSelectorRequirements(pfMonitor.Spec.NodeLabelSelector)
+	overrides := pfMonitor.Spec.NodeOverrides
+
+	excluded := make([]map[string]string, 0, len(overrides))
+	for _, override := range overrides {
+		excluded = append(excluded, override.NodeSelector)
+	}
+
+	groups := make([]relayGroup, 0, len(overrides)+1)
+	groups = append(groups, relayGroup{
+		name:       daemonSetName(pfMonitor),
+		interfaces: pfstatusrelayv1alpha1.CanonicalInterfaces(pfMonitor.Spec.Interfaces),
+		affinity:   nodeAffinity(excludeNodeSelectors(base, excluded)),
+	})
+
+	for i, override := range overrides {
+		requirements := append(slices.Clone(base), mapRequirements(override.NodeSelector)...)
+		groups = append(groups, relayGroup{
+			name:       pfstatusrelayv1alpha1.OverrideDaemonSetName(pfMonitor, override.Name),
+			override:   override.Name,
+			interfaces: pfstatusrelayv1alpha1.CanonicalInterfaces(override.Interfaces),
+			affinity:   nodeAffinity(excludeNodeSelectors(requirements, excluded[:i])),
+		})
+	}
+
+	return groups
+}
+
+// labelSelectorRequirements translates a label selector into node selector requirements.
+func labelSelectorRequirements(selector *metav1.LabelSelector) []corev1.NodeSelectorRequirement {
+	if selector == nil {
+		return nil
+	}
+
+	requirements := mapRequirements(selector.MatchLabels)
+	for _, expr := range selector.MatchExpressions {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      expr.Key,
+			Operator: corev1.NodeSelectorOperator(expr.Operator),
+			Values:   expr.Values,
+		})
+	}
+
+	return requirements
+}
+
+// mapRequirements translates a map based selector into node selector requirements sorted by key.
+func mapRequirements(selector map[string]string) []corev1.NodeSelectorRequirement {
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(keys))
+	for _, key := range keys {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{selector[key]},
+		})
+	}
+
+	return requirements
+}
+
+// excludeNodeSelectors returns the node selector terms matching the nodes that meet the requirements
+// but none of the selectors. A node does not match a selector when at least one of its labels differs,
+// so every selector multiplies the terms by its number of labels.
+func excludeNodeSelectors(requirements []corev1.NodeSelectorRequirement, selectors []map[string]string) []corev1.NodeSelectorTerm {
+	terms := []corev1.NodeSelectorTerm{{MatchExpressions: requirements}}
+	for _, selector := range selectors {
+		excluded := make([]corev1.NodeSelectorTerm, 0, len(terms)*len(selector))
+		for _, term := range terms {
+			for _, requirement := range mapRequirements(selector) {
+				requirement.Operator = corev1.NodeSelectorOpNotIn
+				excluded = append(excluded, corev1.NodeSelectorTerm{
+					MatchExpressions: append(slices.Clone(term.MatchExpressions), requirement),
+				})
+			}
+		}
+		terms = excluded
+	}
+
+	return terms
+}
+
+// nodeAffinity returns a required node affinity matching any of the terms.
+func nodeAffinity(terms []corev1.NodeSelectorTerm) *corev1.Affinity {
+	if len(terms) == 0 || (len(terms) == 1 && len(terms[0].MatchExpressions) == 0 && len(terms[0].MatchFields) == 0) {
+		return nil
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: terms,
+			},
+		},
+	}
+}
+
+// relayAffinity merges the node affinity of a relay group with the affinity of the relay pods set in the monitor.
+// The required node selectors of both must match, so the pod affinity can only restrict the nodes of the group.
+func relayAffinity(groupAffinity, podAffinity *corev1.Affinity) *corev1.Affinity {
+	if podAffinity == nil {
+		return groupAffinity
+	}
+
+	affinity := podAffinity.DeepCopy()
+	if groupAffinity == nil {
+		return affinity
+	}
+
+	groupTerms := groupAffinity.DeepCopy().NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: groupTerms}
+		return affinity
+	}
+
+	// The terms of a node selector are ORed, so every term of the group is combined with every term of the pods
+	terms := make([]corev1.NodeSelectorTerm, 0, len(groupTerms)*len(required.NodeSelectorTerms))
+	for _, groupTerm := range groupTerms {
+		for _, podTerm := range required.NodeSelectorTerms {
+			terms = append(terms, corev1.NodeSelectorTerm{
+				MatchExpressions: append(slices.Clone(groupTerm.MatchExpressions), podTerm.MatchExpressions...),
+				MatchFields:      append(slices.Clone(groupTerm.MatchFields), podTerm.MatchFields...),
+			})
+		}
+	}
+	required.NodeSelectorTerms = terms
+
+	return affinity
+}
+
+// relayLogLevel returns the log level of the relay at the given time: the level of the log level override until it
+// expires, along with the time left until then, and spec.logLevel otherwise.
+func relayLogLevel(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, now time.Time) (string, time.Duration) {
+	override := pfMonitor.Spec.LogLevelOverride
+	if override != nil {
+		if left := override.Until.Sub(now); left > 0 {
+			return override.Level, left
+		}
+	}
+
+	return pfMonitor.Spec.LogLevel, 0
+}
+
+// daemonSetName returns the name of the DaemonSet deployed for a monitor, which is also the
+// value of the app label of its pods.
+func daemonSetName(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) string {
+	return pfstatusrelayv1alpha1.DaemonSetName(pfMonitor)
 }
@@ -21,15 +21,24 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
 )
@@ -98,6 +107,11 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 
 			By("Cleanup the specific resource instance PFLACPMonitor")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			// The monitor is kept until the controller releases its interface claims
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &pfstatusrelayv1alpha1.PFLACPMonitor{}))
+			}, timeout, interval).Should(BeTrue())
 		})
 		Context("Deamonset validation", func() {
 			var ds *appsv1.DaemonSet
@@ -116,6 +130,740 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 				Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"key": "value"}))
 			})
 
+			It("claims the monitored interfaces on the selected nodes", func() {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "worker-claims",
+						Labels: map[string]string{"key": "value"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, node)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+				})
+
+				Eventually(func(g Gomega) {
+					claim := &pfstatusrelayv1alpha1.PFInterfaceClaim{}
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: node.Name}, claim)).To(Succeed())
+					g.Expect(claim.Spec.Claims).To(Equal([]pfstatusrelayv1alpha1.InterfaceClaim{
+						{Interface: "eth0", Monitor: "default/" + resourceName},
+					}))
+					g.Expect(claim.OwnerReferences).To(HaveLen(1))
+					g.Expect(claim.OwnerReferences[0].Name).To(Equal(node.Name))
+				}, timeout, interval).Should(Succeed())
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, monitor)).To(Succeed())
+				Expect(monitor.Finalizers).To(ContainElement(interfaceClaimsFinalizer))
+			})
+
+			It("records an event for the created DaemonSet", func() {
+				Eventually(func() []string {
+					return eventReasons(ctx, typeNamespacedName)
+				}, timeout, interval).Should(ContainElement(pfstatusrelayv1alpha1.EventReasonDaemonSetCreated))
+			})
+
+			It("creates a NetworkPolicy declaring that the relay pods need no traffic", func() {
+				np := &networkingv1.NetworkPolicy{}
+				Eventually(func() error {
+					npName := fmt.Sprintf("%s-np-%s", namePrefix, typeNamespacedName.Name)
+					return k8sClient.Get(ctx, types.NamespacedName{Name: npName, Namespace: typeNamespacedName.Namespace}, np)
+				}, timeout, interval).Should(Succeed())
+
+				Expect(np.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{monitorLabel: resourceName}))
+				Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue(monitorLabel, resourceName))
+				Expect(np.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+				Expect(np.Spec.Ingress).To(BeEmpty())
+				Expect(np.Spec.Egress).To(BeEmpty())
+				Expect(np.OwnerReferences).To(HaveLen(1))
+				Expect(np.OwnerReferences[0].Name).To(Equal(resourceName))
+			})
+
+			It("reports the monitor as Available", func() {
+				Eventually(func() bool {
+					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+					err := k8sClient.Get(ctx, typeNamespacedName, monitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					return monitor.Status.ObservedGeneration == monitor.Generation &&
+						meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionAvailable) &&
+						meta.IsStatusConditionFalse(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionDegraded)
+				}, timeout, interval).Should(BeTrue())
+			})
+
+			It("reports the DaemonSet rollout progress", func() {
+				By("creating a relay pod that is not ready")
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dsName + "-abcde",
+						Namespace: typeNamespacedName.Namespace,
+						Labels:    ds.Spec.Selector.MatchLabels,
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "worker-0",
+						Containers: []corev1.Container{
+							{
+								Name:  relayContainerName,
+								Image: dsImage,
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, pod, client.GracePeriodSeconds(0))).To(Succeed())
+				})
+
+				pod.Status = corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionFalse,
+						},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: relayContainerName,
+							State: corev1.ContainerState{
+								Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+				By("updating the DaemonSet status")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)
+					Expect(err).NotTo(HaveOccurred())
+
+					ds.Status = appsv1.DaemonSetStatus{
+						ObservedGeneration:     ds.Generation,
+						CurrentNumberScheduled: 2,
+						DesiredNumberScheduled: 2,
+						NumberReady:            1,
+						UpdatedNumberScheduled: 2,
+						NumberAvailable:        1,
+						NumberUnavailable:      1,
+					}
+					return k8sClient.Status().Update(ctx, ds)
+				}, timeout, interval).Should(Succeed())
+				DeferCleanup(func() {
+					Eventually(func() error {
+						err := k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)
+						Expect(err).NotTo(HaveOccurred())
+
+						ds.Status = appsv1.DaemonSetStatus{}
+						return k8sClient.Status().Update(ctx, ds)
+					}, timeout, interval).Should(Succeed())
+				})
+
+				By("checking the PFLACPMonitor status")
+				Eventually(func(g Gomega) {
+					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+					g.Expect(k8sClient.Get(ctx, typeNamespacedName, monitor)).To(Succeed())
+
+					g.Expect(monitor.Status.DesiredNumberScheduled).To(Equal(int32(2)))
+					g.Expect(monitor.Status.NumberReady).To(Equal(int32(1)))
+					g.Expect(monitor.Status.UpdatedNumberScheduled).To(Equal(int32(2)))
+					g.Expect(monitor.Status.NumberUnavailable).To(Equal(int32(1)))
+					g.Expect(monitor.Status.UnreadyNodes).To(Equal([]pfstatusrelayv1alpha1.NodeRelayStatus{
+						{
+							NodeName: "worker-0",
+							PodName:  pod.Name,
+							Reason:   "CrashLoopBackOff",
+						},
+					}))
+					g.Expect(meta.IsStatusConditionFalse(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionAvailable)).To(BeTrue())
+					g.Expect(meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionDegraded)).To(BeTrue())
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("renders the node label selector as node affinity", func() {
+				newName := "label-selector-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces: []string{"eth5"},
+						NodeLabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"zone": "a"},
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "sriov-lacp", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				newDs := &appsv1.DaemonSet{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, newDs)
+				}, timeout, interval).Should(Succeed())
+
+				Expect(newDs.Spec.Template.Spec.Affinity).To(Equal(&corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+										{Key: "sriov-lacp", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"disabled"}},
+									},
+								},
+							},
+						},
+					},
+				}))
+			})
+
+			It("sets the relay pod scheduling, resources and metadata of the monitor", func() {
+				newName := "relay-pod-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+
+				tolerations := []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				}
+				resources := corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("10m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+				}
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces: []string{"ens1f0"},
+						NodeOverrides: []pfstatusrelayv1alpha1.NodeInterfaceOverride{
+							{
+								Name:         "sku-b",
+								NodeSelector: map[string]string{"sku": "b"},
+								Interfaces:   []string{"enp94s0f0"},
+							},
+						},
+						RelayPod: &pfstatusrelayv1alpha1.RelayPodTemplate{
+							Labels:      map[string]string{"team": "network", "app": "ignored"},
+							Annotations: map[string]string{"example.com/owner": "network"},
+							Tolerations: tolerations,
+							Affinity: &corev1.Affinity{
+								NodeAffinity: &corev1.NodeAffinity{
+									RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+										NodeSelectorTerms: []corev1.NodeSelectorTerm{
+											{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+											{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+										},
+									},
+								},
+							},
+							Resources: &resources,
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				newDs := &appsv1.DaemonSet{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, newDs)
+				}, timeout, interval).Should(Succeed())
+
+				podTemplate := newDs.Spec.Template
+				Expect(podTemplate.Labels).To(Equal(map[string]string{"app": newDsName, monitorLabel: newName, "team": "network"}))
+				Expect(podTemplate.Annotations).To(Equal(map[string]string{"example.com/owner": "network"}))
+				Expect(podTemplate.Spec.Tolerations).To(Equal(tolerations))
+				Expect(podTemplate.Spec.PriorityClassName).To(Equal("system-node-critical"))
+				Expect(podTemplate.Spec.Containers[0].Resources.Requests.Memory().String()).To(Equal("32Mi"))
+				Expect(podTemplate.Spec.Containers[0].Resources.Limits.Memory().String()).To(Equal("64Mi"))
+
+				By("checking that the pod affinity only restricts the nodes of the DaemonSet")
+				Expect(podTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "sku", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"b"}},
+							{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+						},
+					},
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "sku", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"b"}},
+							{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+						},
+					},
+				}))
+
+				By("setting a priority class")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace}, monitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					monitor.Spec.RelayPod.PriorityClassName = "system-cluster-critical"
+					return k8sClient.Update(ctx, monitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, newDs)).To(Succeed())
+					g.Expect(newDs.Spec.Template.Spec.PriorityClassName).To(Equal("system-cluster-critical"))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("rolls out the operator config to the DaemonSets", func() {
+				configImage := "quay.io/openshift/origin-pf-status-relay@sha256:" + strings.Repeat("a", 64)
+				tolerations := []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+				config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: pfstatusrelayv1alpha1.OperatorConfigName,
+					},
+					Spec: pfstatusrelayv1alpha1.PFStatusRelayOperatorConfigSpec{
+						RelayImage:       configImage,
+						ImagePullPolicy:  corev1.PullIfNotPresent,
+						ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-credentials"}},
+						RelayPodDefaults: &pfstatusrelayv1alpha1.RelayPodTemplate{
+							Labels:            map[string]string{"team": "network"},
+							Tolerations:       tolerations,
+							PriorityClassName: "system-cluster-critical",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, config))).To(Succeed())
+				})
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					podSpec := ds.Spec.Template.Spec
+					g.Expect(podSpec.Containers[0].Image).To(Equal(configImage))
+					g.Expect(podSpec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+					g.Expect(podSpec.ImagePullSecrets).To(Equal(config.Spec.ImagePullSecrets))
+					g.Expect(podSpec.Tolerations).To(Equal(tolerations))
+					g.Expect(podSpec.PriorityClassName).To(Equal("system-cluster-critical"))
+					g.Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue("team", "network"))
+				}, timeout, interval).Should(Succeed())
+
+				By("rejecting an image not pinned by digest")
+				invalid := config.DeepCopy()
+				invalid.Spec.RelayImage = "quay.io/openshift/origin-pf-status-relay:latest"
+				Expect(k8sClient.Update(ctx, invalid)).NotTo(Succeed())
+
+				By("falling back to the image of the environment once the config is deleted")
+				Expect(k8sClient.Delete(ctx, config)).To(Succeed())
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					g.Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal(dsImage))
+					g.Expect(ds.Spec.Template.Spec.Tolerations).To(BeEmpty())
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("rolls out the relay pods to the canary nodes first", func() {
+				newName := "canary-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+
+				for name, nodeLabels := range map[string]map[string]string{
+					"canary-0": {"canary-rollout": "true", "canary": "true"},
+					"canary-1": {"canary-rollout": "true"},
+				} {
+					node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels}}
+					Expect(k8sClient.Create(ctx, node)).To(Succeed())
+					DeferCleanup(func() {
+						Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+					})
+				}
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces:   []string{"ens1f0"},
+						NodeSelector: map[string]string{"canary-rollout": "true"},
+						Rollout: &pfstatusrelayv1alpha1.RolloutStrategy{
+							Canary: &pfstatusrelayv1alpha1.CanaryStrategy{
+								NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+								SoakTime:     &metav1.Duration{Duration: 2 * time.Second},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				newDs := &appsv1.DaemonSet{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, newDs)
+				}, timeout, interval).Should(Succeed())
+				Expect(newDs.Spec.UpdateStrategy.Type).To(Equal(appsv1.OnDeleteDaemonSetStrategyType))
+
+				// The DaemonSet controller does not run in the test environment
+				By("recording a new revision of the DaemonSet")
+				revisionLabels := map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: "rev2"}
+				for key, value := range newDs.Spec.Template.Labels {
+					revisionLabels[key] = value
+				}
+				revision := &appsv1.ControllerRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newDsName + "-rev2",
+						Namespace: typeNamespacedName.Namespace,
+						Labels:    revisionLabels,
+					},
+					Data:     runtime.RawExtension{Raw: []byte("{}")},
+					Revision: 2,
+				}
+				Expect(controllerutil.SetControllerReference(newDs, revision, k8sClient.Scheme())).To(Succeed())
+				Expect(k8sClient.Create(ctx, revision)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, revision)).To(Succeed())
+				})
+
+				createRelayPod := func(node, hash string) *corev1.Pod {
+					podLabels := map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash}
+					for key, value := range newDs.Spec.Selector.MatchLabels {
+						podLabels[key] = value
+					}
+					pod := &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      fmt.Sprintf("%s-%s-%s", newDsName, node, hash),
+							Namespace: typeNamespacedName.Namespace,
+							Labels:    podLabels,
+						},
+						Spec: corev1.PodSpec{
+							Affinity: nodeNameAffinity([]string{node}),
+							Containers: []corev1.Container{
+								{
+									Name:  relayContainerName,
+									Image: dsImage,
+								},
+							},
+						},
+					}
+					Expect(controllerutil.SetControllerReference(newDs, pod, k8sClient.Scheme())).To(Succeed())
+					Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+					DeferCleanup(func() {
+						Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod, client.GracePeriodSeconds(0)))).To(Succeed())
+					})
+
+					pod.Status = corev1.PodStatus{
+						Phase:      corev1.PodRunning,
+						Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+					}
+					Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+					return pod
+				}
+
+				By("running relay pods of the previous revision")
+				canaryPod := createRelayPod("canary-0", "rev1")
+				otherPod := createRelayPod("canary-1", "rev1")
+
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, newDs)
+					Expect(err).NotTo(HaveOccurred())
+
+					newDs.Status = appsv1.DaemonSetStatus{
+						ObservedGeneration:     newDs.Generation,
+						CurrentNumberScheduled: 2,
+						DesiredNumberScheduled: 2,
+						NumberReady:            2,
+						NumberAvailable:        2,
+					}
+					return k8sClient.Status().Update(ctx, newDs)
+				}, timeout, interval).Should(Succeed())
+
+				By("checking that only the canary pod is replaced")
+				Eventually(func() bool {
+					return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(canaryPod), &corev1.Pod{}))
+				}, timeout, interval).Should(BeTrue())
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(otherPod), &corev1.Pod{})).To(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace}, monitor)).To(Succeed())
+					g.Expect(monitor.Status.Rollout).NotTo(BeNil())
+					g.Expect(monitor.Status.Rollout.Phase).To(Equal(pfstatusrelayv1alpha1.RolloutPhaseCanary))
+					g.Expect(monitor.Status.Rollout.CanaryNodes).To(Equal(int32(1)))
+				}, timeout, interval).Should(Succeed())
+
+				By("running a ready canary pod of the new revision")
+				createRelayPod("canary-0", "rev2")
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace}, monitor)).To(Succeed())
+					g.Expect(monitor.Status.Rollout.Phase).To(Equal(pfstatusrelayv1alpha1.RolloutPhaseSoaking))
+					g.Expect(monitor.Status.Rollout.UpdatedCanaryNodes).To(Equal(int32(1)))
+					g.Expect(monitor.Status.Rollout.CanaryReadyTime).NotTo(BeNil())
+				}, timeout, interval).Should(Succeed())
+
+				By("checking that the other nodes follow after the soak time")
+				Eventually(func() bool {
+					return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(otherPod), &corev1.Pod{}))
+				}, timeout, interval).Should(BeTrue())
+				Eventually(func() []string {
+					return eventReasons(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace})
+				}, timeout, interval).Should(ContainElement(pfstatusrelayv1alpha1.EventReasonCanaryPromoted))
+			})
+
+			It("holds the DaemonSet changes while the rollout is paused", func() {
+				By("pausing the rollout")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					pflacpmonitor.Spec.Rollout = &pfstatusrelayv1alpha1.RolloutStrategy{Paused: true}
+					return k8sClient.Update(ctx, pflacpmonitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)).To(Succeed())
+					g.Expect(pflacpmonitor.Status.Rollout).NotTo(BeNil())
+					g.Expect(pflacpmonitor.Status.Rollout.Phase).To(Equal(pfstatusrelayv1alpha1.RolloutPhasePaused))
+				}, timeout, interval).Should(Succeed())
+
+				By("changing the polling interval")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					pflacpmonitor.Spec.PollingInterval = 3000
+					return k8sClient.Update(ctx, pflacpmonitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)).To(Succeed())
+					g.Expect(pflacpmonitor.Status.Rollout.Message).To(ContainSubstring("held"))
+				}, timeout, interval).Should(Succeed())
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+				Expect(ds.Spec.Template.Spec.Containers[0].Env).To(Equal(envVars))
+
+				By("resuming the rollout")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					pflacpmonitor.Spec.Rollout = &pfstatusrelayv1alpha1.RolloutStrategy{MaxUnavailable: ptr.To(intstr.FromString("50%"))}
+					return k8sClient.Update(ctx, pflacpmonitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					g.Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_POLLING_INTERVAL", Value: "3000"}))
+					g.Expect(ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).To(Equal(ptr.To(intstr.FromString("50%"))))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("renders the relay log level until the override expires", func() {
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typeNamespacedName, pflacpmonitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					pflacpmonitor.Spec.LogLevel = pfstatusrelayv1alpha1.LogLevelWarn
+					pflacpmonitor.Spec.LogLevelOverride = &pfstatusrelayv1alpha1.LogLevelOverride{
+						Level: pfstatusrelayv1alpha1.LogLevelDebug,
+						Until: metav1.NewTime(time.Now().Add(3 * time.Second)),
+					}
+					return k8sClient.Update(ctx, pflacpmonitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					g.Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_LOG_LEVEL", Value: "debug"}))
+				}, timeout, interval).Should(Succeed())
+
+				By("reverting to the log level of the monitor once the override expires")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					g.Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_LOG_LEVEL", Value: "warn"}))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("creates a DaemonSet per node override", func() {
+				newName := "override-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+				overrideDsName := newDsName + "-o-" + pfstatusrelayv1alpha1.NameHash("sku-b")
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces:   []string{"ens1f0"},
+						NodeSelector: map[string]string{"override": "true"},
+						NodeOverrides: []pfstatusrelayv1alpha1.NodeInterfaceOverride{
+							{
+								Name:         "sku-b",
+								NodeSelector: map[string]string{"sku": "b"},
+								Interfaces:   []string{"enp94s0f0"},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				By("checking the DaemonSet of the nodes without override")
+				defaultDs := &appsv1.DaemonSet{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, defaultDs)
+				}, timeout, interval).Should(Succeed())
+
+				Expect(defaultDs.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_INTERFACES", Value: "ens1f0"}))
+				Expect(defaultDs.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"override": "true"}))
+				Expect(defaultDs.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "sku", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"b"}},
+						},
+					},
+				}))
+
+				By("checking the DaemonSet of the override")
+				overrideDs := &appsv1.DaemonSet{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: overrideDsName, Namespace: typeNamespacedName.Namespace}, overrideDs)
+				}, timeout, interval).Should(Succeed())
+
+				Expect(overrideDs.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_INTERFACES", Value: "enp94s0f0"}))
+				Expect(overrideDs.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"override": "true"}))
+				Expect(overrideDs.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "sku", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+						},
+					},
+				}))
+
+				By("removing the override")
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace}, monitor)
+					Expect(err).NotTo(HaveOccurred())
+
+					monitor.Spec.NodeOverrides = nil
+					return k8sClient.Update(ctx, monitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: overrideDsName, Namespace: typeNamespacedName.Namespace}, overrideDs)
+					return errors.IsNotFound(err)
+				}, timeout, interval).Should(BeTrue())
+			})
+
+			It("resolves interface selectors against the node states", func() {
+				newName := "selector-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+
+				for _, name := range []string{"selector-0", "selector-1"} {
+					node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{"selector": "true"},
+					}}
+					Expect(k8sClient.Create(ctx, node)).To(Succeed())
+					DeferCleanup(func() {
+						Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+					})
+				}
+
+				// Only selector-0 reports its interfaces
+				nodeState := &pfstatusrelayv1alpha1.PFLACPNodeState{ObjectMeta: metav1.ObjectMeta{Name: "selector-0"}}
+				Expect(k8sClient.Create(ctx, nodeState)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, nodeState)).To(Succeed())
+				})
+				now := metav1.Now()
+				nodeState.Status = pfstatusrelayv1alpha1.PFLACPNodeStateStatus{
+					Interfaces: []pfstatusrelayv1alpha1.PFStatus{
+						{
+							HostInterface: pfstatusrelayv1alpha1.HostInterface{Name: "ens1f1", Driver: "ice"},
+							LACP:          &pfstatusrelayv1alpha1.LACPStatus{Synchronized: false},
+							VFLinkState:   "disable",
+						},
+						{
+							HostInterface: pfstatusrelayv1alpha1.HostInterface{Name: "ens1f0", Driver: "ice"},
+							LACP:          &pfstatusrelayv1alpha1.LACPStatus{Synchronized: true},
+							VFLinkState:   "auto",
+						},
+						{HostInterface: pfstatusrelayv1alpha1.HostInterface{Name: "ens2f0", Driver: "mlx5_core"}},
+					},
+					LastUpdateTime: &now,
+				}
+				Expect(k8sClient.Status().Update(ctx, nodeState)).To(Succeed())
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces:   []string{"driver=ice"},
+						NodeSelector: map[string]string{"selector": "true"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				By("checking the DaemonSet of the resolved interfaces")
+				dsList := &appsv1.DaemonSetList{}
+				Eventually(func() ([]appsv1.DaemonSet, error) {
+					err := k8sClient.List(ctx, dsList, client.InNamespace(typeNamespacedName.Namespace), client.MatchingLabels{monitorLabel: newName})
+					return dsList.Items, err
+				}, timeout, interval).Should(HaveLen(1))
+
+				ds := dsList.Items[0]
+				Expect(ds.Name).To(HavePrefix(newDsName + "-"))
+				Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "PF_STATUS_RELAY_INTERFACES", Value: "ens1f0,ens1f1"}))
+				Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+					{
+						MatchFields: []corev1.NodeSelectorRequirement{
+							{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"selector-0"}},
+						},
+					},
+				}))
+
+				By("checking the interfaces not found")
+				Eventually(func() *metav1.Condition {
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: typeNamespacedName.Namespace}, monitor)).To(Succeed())
+					return meta.FindStatusCondition(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionMissingInterfaces)
+				}, timeout, interval).Should(And(
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Reason", pfstatusrelayv1alpha1.ReasonInterfacesNotFound),
+					HaveField("Message", "interfaces not found on nodes: selector-1 [driver=ice]"),
+				))
+
+				By("checking the state of the interfaces")
+				Expect(monitor.Status.Interfaces).To(HaveLen(2))
+				Expect(monitor.Status.Interfaces[0]).To(And(
+					HaveField("NodeName", "selector-0"),
+					HaveField("Name", "ens1f0"),
+					HaveField("LACP", pfstatusrelayv1alpha1.LACPStateUp),
+					HaveField("VFLinkState", "auto"),
+				))
+				Expect(monitor.Status.Interfaces[1]).To(And(
+					HaveField("NodeName", "selector-0"),
+					HaveField("Name", "ens1f1"),
+					HaveField("LACP", pfstatusrelayv1alpha1.LACPStateDown),
+					HaveField("VFLinkState", "disable"),
+				))
+			})
+
 			It("recreates the DeamonSet when this has been deleted", func() {
 				By("deleting the DeamonSet")
 				Expect(k8sClient.Delete(ctx, ds)).To(Succeed())
@@ -147,11 +895,150 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 				}, timeout, interval).Should(BeTrue())
 			})
 
+			It("applies the DaemonSet with the operator field manager", func() {
+				Expect(ds.ManagedFields).To(ContainElement(SatisfyAll(
+					HaveField("Manager", fieldManager),
+					HaveField("Operation", metav1.ManagedFieldsOperationApply),
+				)))
+			})
+
+			It("keeps the DaemonSet fields set by others", func() {
+				By("setting a toleration and an annotation with another field manager")
+				patch := client.MergeFrom(ds.DeepCopy())
+				ds.Annotations = map[string]string{"example.com/owner": "admin"}
+				ds.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "example.com/lacp", Operator: corev1.TolerationOpExists}}
+				Expect(k8sClient.Patch(ctx, ds, patch, client.FieldOwner("admin"))).To(Succeed())
+				resourceVersion := ds.ResourceVersion
+
+				By("triggering a reconcile")
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, monitor)).To(Succeed())
+				monitor.Annotations = map[string]string{"example.com/reconcile": "now"}
+				Expect(k8sClient.Update(ctx, monitor)).To(Succeed())
+
+				By("checking that the DaemonSet is not written")
+				Consistently(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: typeNamespacedName.Namespace}, ds)).To(Succeed())
+					g.Expect(ds.ResourceVersion).To(Equal(resourceVersion))
+				}, time.Second*2, interval).Should(Succeed())
+				Expect(ds.Annotations).To(HaveKeyWithValue("example.com/owner", "admin"))
+				Expect(ds.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+			})
+
+			It("drops the DaemonSet fields written before the DaemonSet was applied", func() {
+				newName := "legacy-monitor"
+				newDsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
+
+				By("creating the DaemonSet with the legacy field manager")
+				legacyDs := &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newDsName,
+						Namespace: typeNamespacedName.Namespace,
+						Labels:    map[string]string{monitorLabel: newName},
+					},
+					Spec: appsv1.DaemonSetSpec{
+						Selector:        &metav1.LabelSelector{MatchLabels: map[string]string{"app": newDsName}},
+						MinReadySeconds: 30,
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": newDsName}},
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: relayContainerName, Image: dsImage}},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, legacyDs, client.FieldOwner(legacyFieldManager))).To(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, legacyDs))).To(Succeed())
+				})
+
+				monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      newName,
+						Namespace: typeNamespacedName.Namespace,
+					},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces:   []string{"ens9f0"},
+						NodeSelector: map[string]string{"legacy": "true"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, monitor)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, monitor)).To(Succeed())
+				})
+
+				By("checking that the fields of the legacy field manager are handed over and dropped")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: newDsName, Namespace: typeNamespacedName.Namespace}, legacyDs)).To(Succeed())
+					g.Expect(legacyDs.Spec.MinReadySeconds).To(BeZero())
+					g.Expect(legacyDs.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"legacy": "true"}))
+					g.Expect(legacyDs.ManagedFields).NotTo(ContainElement(HaveField("Manager", legacyFieldManager)))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			It("recovers a monitor when the monitor it lost to changes", func() {
+				peerName := types.NamespacedName{Name: "peer-monitor", Namespace: typeNamespacedName.Namespace}
+
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "worker-peer",
+						Labels: map[string]string{"key": "value"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, node)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+				})
+
+				peer := &pfstatusrelayv1alpha1.PFLACPMonitor{
+					ObjectMeta: metav1.ObjectMeta{Name: peerName.Name, Namespace: peerName.Namespace},
+					Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+						Interfaces: []string{"eth0"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, peer)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, peer)).To(Succeed())
+				})
+
+				Eventually(func(g Gomega) {
+					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+					g.Expect(k8sClient.Get(ctx, peerName, monitor)).To(Succeed())
+					g.Expect(meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict)).To(BeTrue())
+				}, timeout, interval).Should(Succeed())
+
+				By("moving the winning monitor to other interfaces")
+				Eventually(func() error {
+					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+					Expect(k8sClient.Get(ctx, typeNamespacedName, monitor)).To(Succeed())
+					monitor.Spec.Interfaces = []string{"eth9"}
+					return k8sClient.Update(ctx, monitor)
+				}, timeout, interval).Should(Succeed())
+
+				Eventually(func(g Gomega) {
+					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
+					g.Expect(k8sClient.Get(ctx, peerName, monitor)).To(Succeed())
+					g.Expect(meta.IsStatusConditionFalse(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict)).To(BeTrue())
+				}, timeout, interval).Should(Succeed())
+			})
+
 			It("should modify Degraded status appropriately", func() {
 				newName := "new-monitor"
 				namespace := "default"
 				dsName := fmt.Sprintf("%s-ds-%s", namePrefix, newName)
 
+				By("creating a node selected by both PFLACPMonitor resources")
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "worker-0",
+						Labels: map[string]string{"key": "value"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, node)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+				})
+
 				By("creating a new PFLACPMonitor resource")
 				newPFLACPMonitor := &pfstatusrelayv1alpha1.PFLACPMonitor{
 					ObjectMeta: metav1.ObjectMeta{
@@ -187,7 +1074,9 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 					err := k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: namespace}, monitor)
 					Expect(err).NotTo(HaveOccurred())
 
-					return reflect.DeepEqual(monitor.Status.Degraded, true)
+					return meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionDegraded) &&
+						meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict) &&
+						monitor.Status.WinningMonitor == typeNamespacedName.String()
 				}, timeout, interval).Should(BeTrue())
 
 				ds = &appsv1.DaemonSet{}
@@ -196,6 +1085,13 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 					return err
 				}, timeout, interval).ShouldNot(Succeed())
 
+				Eventually(func() []string {
+					return eventReasons(ctx, types.NamespacedName{Name: newName, Namespace: namespace})
+				}, timeout, interval).Should(ContainElements(
+					pfstatusrelayv1alpha1.EventReasonInterfaceConflict,
+					pfstatusrelayv1alpha1.EventReasonDaemonSetDeleted,
+				))
+
 				By("Updating the new PFLACPMonitor resource with different interfaces")
 				Eventually(func() error {
 					monitor := &pfstatusrelayv1alpha1.PFLACPMonitor{}
@@ -212,7 +1108,9 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 					err := k8sClient.Get(ctx, types.NamespacedName{Name: newName, Namespace: namespace}, monitor)
 					Expect(err).NotTo(HaveOccurred())
 
-					return reflect.DeepEqual(monitor.Status.Degraded, false)
+					return meta.IsStatusConditionFalse(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionDegraded) &&
+						meta.IsStatusConditionFalse(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict) &&
+						monitor.Status.WinningMonitor == ""
 				}, timeout, interval).Should(BeTrue())
 
 				ds = &appsv1.DaemonSet{}
@@ -220,7 +1118,57 @@ var _ = Describe("PFLACPMonitor Controller", func() {
 					err := k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: namespace}, ds)
 					return err
 				}, timeout, interval).Should(Succeed())
+
+				Eventually(func() []string {
+					return eventReasons(ctx, types.NamespacedName{Name: newName, Namespace: namespace})
+				}, timeout, interval).Should(ContainElement(pfstatusrelayv1alpha1.EventReasonConflictResolved))
 			})
 		})
 	})
 })
+
+var _ = Describe("Unready nodes", func() {
+	unreadyPod := func(name, nodeName string, created time.Time, reason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:  relayContainerName,
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+					},
+				},
+			},
+		}
+	}
+
+	It("reports each node once, with its newest pod, during a surge rollout", func() {
+		created := time.Now().Add(-time.Minute)
+		pods := []*corev1.Pod{
+			unreadyPod("relay-new", "worker-0", created.Add(time.Second), "ContainerCreating"),
+			unreadyPod("relay-old", "worker-0", created, "CrashLoopBackOff"),
+			unreadyPod("relay-other", "worker-1", created, "CrashLoopBackOff"),
+		}
+
+		Expect(unreadyNodes(pods)).To(Equal([]pfstatusrelayv1alpha1.NodeRelayStatus{
+			{NodeName: "worker-0", PodName: "relay-new", Reason: "ContainerCreating"},
+			{NodeName: "worker-1", PodName: "relay-other", Reason: "CrashLoopBackOff"},
+		}))
+		Expect(unreadyNodes(nil)).To(BeNil())
+	})
+})
+
+// eventReasons returns the reasons of the events recorded for a monitor.
+func eventReasons(ctx context.Context, name types.NamespacedName) []string {
+	eventList := &corev1.EventList{}
+	Expect(k8sClient.List(ctx, eventList, client.InNamespace(name.Namespace))).To(Succeed())
+
+	var reasons []string
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind == "PFLACPMonitor" && event.InvolvedObject.Name == name.Name {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	return reasons
+}
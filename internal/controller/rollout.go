@@ -0,0 +1,258 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+// syncRolloutStatus copies the rollout progress of the monitor DaemonSets into the monitor status
+// and sets the Available, Progressing and Degraded conditions from it. held reports DaemonSet changes
+// held by a paused rollout, and canaryStatus the progress of a canary rollout, if any.
+func (r *PFLACPMonitorReconciler) syncRolloutStatus(ctx context.Context, pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor, daemonSets []*appsv1.DaemonSet, held bool, canaryStatus *pfstatusrelayv1alpha1.RolloutStatus) error {
+	var status appsv1.DaemonSetStatus
+	var unreadyPods []*corev1.Pod
+	progressing := false
+	for _, ds := range daemonSets {
+		pods, err := r.getUnreadyPods(ctx, ds)
+		if err != nil {
+			return fmt.Errorf("failed to list relay pods: %w", err)
+		}
+		unreadyPods = append(unreadyPods, pods...)
+
+		status.DesiredNumberScheduled += ds.Status.DesiredNumberScheduled
+		status.NumberReady += ds.Status.NumberReady
+		status.UpdatedNumberScheduled += ds.Status.UpdatedNumberScheduled
+		status.NumberUnavailable += ds.Status.NumberUnavailable
+
+		if ds.Status.ObservedGeneration < ds.Generation || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+			progressing = true
+		}
+	}
+
+	pfMonitor.Status.DesiredNumberScheduled = status.DesiredNumberScheduled
+	pfMonitor.Status.NumberReady = status.NumberReady
+	pfMonitor.Status.UpdatedNumberScheduled = status.UpdatedNumberScheduled
+	pfMonitor.Status.NumberUnavailable = status.NumberUnavailable
+	pfMonitor.Status.UnreadyNodes = unreadyNodes(unreadyPods)
+
+	msg := fmt.Sprintf("%d of %d nodes run the latest relay pod", status.UpdatedNumberScheduled, status.DesiredNumberScheduled)
+	switch {
+	case pfMonitor.Spec.Rollout != nil && pfMonitor.Spec.Rollout.Paused:
+		progressing = false
+		msg = "the rollout is paused"
+		if held {
+			msg = "the rollout is paused, changes to the relay pods are held"
+		}
+		pfMonitor.Status.Rollout = &pfstatusrelayv1alpha1.RolloutStatus{Phase: pfstatusrelayv1alpha1.RolloutPhasePaused, Message: msg}
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonRolloutPaused, msg)
+	case canaryStatus != nil:
+		progressing = canaryStatus.Phase != pfstatusrelayv1alpha1.RolloutPhaseComplete
+		pfMonitor.Status.Rollout = canaryStatus
+		if progressing {
+			setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonRollingOut, canaryStatus.Message)
+		} else {
+			setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonAsExpected, "")
+		}
+	case progressing:
+		pfMonitor.Status.Rollout = &pfstatusrelayv1alpha1.RolloutStatus{Phase: pfstatusrelayv1alpha1.RolloutPhaseRollingOut, Message: msg}
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonRollingOut, msg)
+	default:
+		pfMonitor.Status.Rollout = &pfstatusrelayv1alpha1.RolloutStatus{Phase: pfstatusrelayv1alpha1.RolloutPhaseComplete}
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionProgressing, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonAsExpected, "")
+	}
+
+	if status.NumberUnavailable == 0 {
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionAvailable, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonRelayPodsReady, "")
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionDegraded, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonAsExpected, "")
+		return nil
+	}
+
+	msg = fmt.Sprintf("%d of %d relay pods are unavailable", status.NumberUnavailable, status.DesiredNumberScheduled)
+	setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionAvailable, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonRelayPodsUnavailable, msg)
+
+	// Pods are expected to be unavailable while they are being replaced.
+	if progressing {
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionDegraded, metav1.ConditionFalse, pfstatusrelayv1alpha1.ReasonAsExpected, "")
+	} else {
+		setCondition(pfMonitor, pfstatusrelayv1alpha1.ConditionDegraded, metav1.ConditionTrue, pfstatusrelayv1alpha1.ReasonRelayPodsUnavailable, msg)
+	}
+
+	return nil
+}
+
+// clearRolloutStatus resets the rollout progress of a monitor without DaemonSet.
+func clearRolloutStatus(pfMonitor *pfstatusrelayv1alpha1.PFLACPMonitor) {
+	pfMonitor.Status.DesiredNumberScheduled = 0
+	pfMonitor.Status.NumberReady = 0
+	pfMonitor.Status.UpdatedNumberScheduled = 0
+	pfMonitor.Status.NumberUnavailable = 0
+	pfMonitor.Status.UnreadyNodes = nil
+	pfMonitor.Status.Rollout = nil
+}
+
+// daemonSetUpdateStrategy returns the update strategy of the relay DaemonSets. During a canary rollout,
+// the relay pods are replaced by the operator, so the DaemonSets are updated on delete.
+func daemonSetUpdateStrategy(rollout *pfstatusrelayv1alpha1.RolloutStrategy) appsv1.DaemonSetUpdateStrategy {
+	if rollout == nil {
+		return appsv1.DaemonSetUpdateStrategy{}
+	}
+
+	if rollout.Type == appsv1.OnDeleteDaemonSetStrategyType || rollout.Canary != nil {
+		return appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+	}
+
+	strategy := appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType}
+	if rollout.MaxUnavailable != nil || rollout.MaxSurge != nil {
+		strategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+			MaxUnavailable: rollout.MaxUnavailable,
+			MaxSurge:       rollout.MaxSurge,
+		}
+	}
+	return strategy
+}
+
+// getUnreadyPods returns the relay pods of the DaemonSet that are not ready on a node.
+func (r *PFLACPMonitorReconciler) getUnreadyPods(ctx context.Context, ds *appsv1.DaemonSet) ([]*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList, client.InNamespace(ds.Namespace), client.MatchingLabels(ds.Spec.Selector.MatchLabels))
+	if err != nil {
+		return nil, err
+	}
+
+	var unreadyPods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || isPodReady(pod) || podNodeName(pod) == "" {
+			continue
+		}
+		unreadyPods = append(unreadyPods, pod)
+	}
+
+	return unreadyPods, nil
+}
+
+// unreadyNodes returns the nodes of the unready relay pods, sorted by name. The status lists the nodes once, so
+// when a node has several unready pods, as during a surge rollout, the newest one is reported.
+func unreadyNodes(pods []*corev1.Pod) []pfstatusrelayv1alpha1.NodeRelayStatus {
+	newest := make(map[string]*corev1.Pod, len(pods))
+	for _, pod := range pods {
+		nodeName := podNodeName(pod)
+		if other, found := newest[nodeName]; !found || isNewerPod(pod, other) {
+			newest[nodeName] = pod
+		}
+	}
+
+	unreadyNodes := make([]pfstatusrelayv1alpha1.NodeRelayStatus, 0, len(newest))
+	for nodeName, pod := range newest {
+		reason, message := podNotReadyReason(pod)
+		unreadyNodes = append(unreadyNodes, pfstatusrelayv1alpha1.NodeRelayStatus{
+			NodeName: nodeName,
+			PodName:  pod.Name,
+			Reason:   reason,
+			Message:  message,
+		})
+	}
+	if len(unreadyNodes) == 0 {
+		return nil
+	}
+
+	sort.Slice(unreadyNodes, func(i, j int) bool {
+		return unreadyNodes[i].NodeName < unreadyNodes[j].NodeName
+	})
+	return unreadyNodes
+}
+
+// isNewerPod checks whether pod a was created after pod b, comparing the names of pods created in the same second.
+func isNewerPod(a, b *corev1.Pod) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return b.CreationTimestamp.Before(&a.CreationTimestamp)
+	}
+	return a.Name > b.Name
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podNotReadyReason returns the waiting or terminated reason of the relay container,
+// falling back to the pod scheduling condition and phase.
+func podNotReadyReason(pod *corev1.Pod) (string, string) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != relayContainerName {
+			continue
+		}
+		if cs.State.Waiting != nil {
+			return cs.State.Waiting.Reason, cs.State.Waiting.Message
+		}
+		if cs.State.Terminated != nil {
+			return cs.State.Terminated.Reason, cs.State.Terminated.Message
+		}
+		if cs.LastTerminationState.Terminated != nil {
+			return cs.LastTerminationState.Terminated.Reason, cs.LastTerminationState.Terminated.Message
+		}
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			return c.Reason, c.Message
+		}
+	}
+
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason, pod.Status.Message
+	}
+
+	return string(pod.Status.Phase), ""
+}
+
+// podNodeName returns the node a DaemonSet pod runs on or, when not scheduled yet,
+// the node it is pinned to through its node affinity.
+func podNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == "metadata.name" && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+
+	return ""
+}
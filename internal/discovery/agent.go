@@ -0,0 +1,170 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+)
+
+// Agent periodically discovers the PFs of a node and reports them in the PFLACPNodeState named after the node.
+type Agent struct {
+	client.Client
+
+	// NodeName is the name of the node the agent runs on
+	NodeName string
+	// Namespace is the namespace of the PFLACPMonitors covering the node
+	Namespace string
+	// SysfsRoot is the path where the sysfs of the node is mounted
+	SysfsRoot string
+	// Interval is the time between two discoveries
+	Interval time.Duration
+}
+
+// Start runs the discovery until the context is cancelled.
+func (a *Agent) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Sync(ctx); err != nil {
+			log.Log.Error("failed to sync node state", "node", a.NodeName, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync discovers the PFs of the node and updates its PFLACPNodeState if they changed.
+func (a *Agent) Sync(ctx context.Context) error {
+	pfs, err := Discover(a.SysfsRoot)
+	if err != nil {
+		return err
+	}
+
+	// The LACP state is still worth reporting without the VF link states
+	vfStates, err := listVFLinkStates()
+	if err != nil {
+		log.Log.Error("failed to list VF link states", "node", a.NodeName, "error", err)
+	}
+	for i := range pfs {
+		pfs[i].VFLinkState = vfLinkState(vfStates[pfs[i].Name])
+	}
+
+	node := &corev1.Node{}
+	if err := a.Get(ctx, client.ObjectKey{Name: a.NodeName}, node); err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+
+	pfMonitorList := &pfstatusrelayv1alpha1.PFLACPMonitorList{}
+	if err := a.List(ctx, pfMonitorList, client.InNamespace(a.Namespace)); err != nil {
+		return fmt.Errorf("failed to list PFLACPMonitor: %w", err)
+	}
+
+	if err := setMonitoredBy(pfs, node, pfMonitorList); err != nil {
+		return err
+	}
+
+	nodeState := &pfstatusrelayv1alpha1.PFLACPNodeState{}
+	err = a.Get(ctx, client.ObjectKey{Name: a.NodeName}, nodeState)
+	if apierrors.IsNotFound(err) {
+		log.Log.Info("creating node state", "node", a.NodeName)
+		nodeState = &pfstatusrelayv1alpha1.PFLACPNodeState{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: a.NodeName,
+			},
+		}
+		// Delete the node state along with the node
+		if err := controllerutil.SetOwnerReference(node, nodeState, a.Scheme()); err != nil {
+			return err
+		}
+		if err := a.Create(ctx, nodeState); err != nil {
+			return fmt.Errorf("failed to create node state: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get node state: %w", err)
+	}
+
+	if nodeState.Status.LastUpdateTime != nil && equality.Semantic.DeepEqual(nodeState.Status.Interfaces, pfs) {
+		return nil
+	}
+
+	log.Log.Info("updating node state", "node", a.NodeName, "interfaces", len(pfs))
+	now := metav1.Now()
+	nodeState.Status = pfstatusrelayv1alpha1.PFLACPNodeStateStatus{
+		Interfaces:     pfs,
+		LastUpdateTime: &now,
+	}
+	return a.Status().Update(ctx, nodeState)
+}
+
+// setMonitoredBy records the monitors covering each PF of the node.
+// Monitors whose interfaces conflict with other monitors are ignored, since they do not deploy the relay.
+func setMonitoredBy(pfs []pfstatusrelayv1alpha1.PFStatus, node *corev1.Node, pfMonitorList *pfstatusrelayv1alpha1.PFLACPMonitorList) error {
+	ifaces := HostInterfaces(pfs)
+
+	for _, monitor := range pfMonitorList.Items {
+		if meta.IsStatusConditionTrue(monitor.Status.Conditions, pfstatusrelayv1alpha1.ConditionInterfaceConflict) {
+			continue
+		}
+
+		entries, _, err := pfstatusrelayv1alpha1.NodeInterfaces(&monitor, node)
+		if err != nil {
+			return err
+		}
+
+		names, err := pfstatusrelayv1alpha1.ResolveInterfaces(entries, ifaces)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			for i := range pfs {
+				if pfs[i].Name == name && pfs[i].MonitoredBy == "" {
+					pfs[i].MonitoredBy = monitor.Namespace + "/" + monitor.Name
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// HostInterfaces returns the interface descriptions of the PFs.
+func HostInterfaces(pfs []pfstatusrelayv1alpha1.PFStatus) []pfstatusrelayv1alpha1.HostInterface {
+	ifaces := make([]pfstatusrelayv1alpha1.HostInterface, 0, len(pfs))
+	for _, pf := range pfs {
+		ifaces = append(ifaces, pf.HostInterface)
+	}
+	return ifaces
+}
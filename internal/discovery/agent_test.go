@@ -0,0 +1,107 @@
+package discovery
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent", func() {
+	var (
+		ctx   context.Context
+		agent *Agent
+		c     client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(pfstatusrelayv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-0",
+			Labels: map[string]string{"example.com/sku": "b"},
+		}}
+		monitors := []client.Object{
+			&pfstatusrelayv1alpha1.PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor-a", Namespace: "default"},
+				Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+					Interfaces: []string{"ens1f0"},
+					NodeOverrides: []pfstatusrelayv1alpha1.NodeInterfaceOverride{{
+						Name:         "sku-b",
+						NodeSelector: map[string]string{"example.com/sku": "b"},
+						Interfaces:   []string{"driver=ice"},
+					}},
+				},
+			},
+			&pfstatusrelayv1alpha1.PFLACPMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor-b", Namespace: "default"},
+				Spec: pfstatusrelayv1alpha1.PFLACPMonitorSpec{
+					Interfaces:   []string{"ens2f0"},
+					NodeSelector: map[string]string{"example.com/sku": "a"},
+				},
+			},
+		}
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).WithObjects(monitors...).
+			WithStatusSubresource(&pfstatusrelayv1alpha1.PFLACPNodeState{}).Build()
+
+		sysfs := &fakeSysfs{root: GinkgoT().TempDir()}
+		sysfs.pf("ens1f0", "0000:3b:00.0", "0x8086", "0x159b", "ice", "64", "8")
+		sysfs.pf("ens2f0", "0000:5e:00.0", "0x15b3", "0x1017", "mlx5_core", "16", "0")
+
+		vfLinkStates := listVFLinkStates
+		listVFLinkStates = func() (map[string][]uint32, error) {
+			return map[string][]uint32{"ens1f0": {vfLinkStateDisable, vfLinkStateDisable}}, nil
+		}
+		DeferCleanup(func() {
+			listVFLinkStates = vfLinkStates
+		})
+
+		agent = &Agent{
+			Client:    c,
+			NodeName:  "worker-0",
+			Namespace: "default",
+			SysfsRoot: sysfs.root,
+		}
+	})
+
+	It("reports the PFs of the node and the monitors covering them", func() {
+		Expect(agent.Sync(ctx)).To(Succeed())
+
+		nodeState := &pfstatusrelayv1alpha1.PFLACPNodeState{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "worker-0"}, nodeState)).To(Succeed())
+		Expect(nodeState.OwnerReferences).To(HaveLen(1))
+		Expect(nodeState.OwnerReferences[0].Name).To(Equal("worker-0"))
+		Expect(nodeState.Status.LastUpdateTime).NotTo(BeNil())
+
+		Expect(nodeState.Status.Interfaces).To(HaveLen(2))
+		Expect(nodeState.Status.Interfaces[0].Name).To(Equal("ens1f0"))
+		Expect(nodeState.Status.Interfaces[0].MonitoredBy).To(Equal("default/monitor-a"))
+		Expect(nodeState.Status.Interfaces[0].VFLinkState).To(Equal("disable"))
+		Expect(nodeState.Status.Interfaces[1].Name).To(Equal("ens2f0"))
+		Expect(nodeState.Status.Interfaces[1].MonitoredBy).To(BeEmpty())
+	})
+
+	It("does not update the node state when nothing changed", func() {
+		Expect(agent.Sync(ctx)).To(Succeed())
+
+		nodeState := &pfstatusrelayv1alpha1.PFLACPNodeState{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "worker-0"}, nodeState)).To(Succeed())
+		resourceVersion := nodeState.ResourceVersion
+
+		Expect(agent.Sync(ctx)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "worker-0"}, nodeState)).To(Succeed())
+		Expect(nodeState.ResourceVersion).To(Equal(resourceVersion))
+	})
+})
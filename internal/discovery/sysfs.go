@@ -0,0 +1,196 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+const (
+	// bondModeLACP is the bonding mode using LACP
+	bondModeLACP = "802.3ad"
+
+	// portStateInSync is the mask of the synchronization, collecting and distributing bits of an LACP port state
+	portStateInSync = 0x08 | 0x10 | 0x20
+)
+
+// Discover returns the SR-IOV physical functions found in the sysfs mounted at sysfsRoot, sorted by name.
+func Discover(sysfsRoot string) ([]pfstatusrelayv1alpha1.PFStatus, error) {
+	netDir := filepath.Join(sysfsRoot, "class", "net")
+	entries, err := os.ReadDir(netDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	pfs := make([]pfstatusrelayv1alpha1.PFStatus, 0)
+	for _, entry := range entries {
+		pf, err := discoverPF(netDir, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover interface %s: %w", entry.Name(), err)
+		}
+		if pf != nil {
+			pfs = append(pfs, *pf)
+		}
+	}
+
+	sort.Slice(pfs, func(i, j int) bool {
+		return pfs[i].Name < pfs[j].Name
+	})
+
+	return pfs, nil
+}
+
+// discoverPF returns the description of an interface, or nil if it is not an SR-IOV physical function.
+func discoverPF(netDir, name string) (*pfstatusrelayv1alpha1.PFStatus, error) {
+	ifDir := filepath.Join(netDir, name)
+	deviceDir := filepath.Join(ifDir, "device")
+
+	totalVFs, err := readInt(filepath.Join(deviceDir, "sriov_totalvfs"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pf := &pfstatusrelayv1alpha1.PFStatus{
+		HostInterface: pfstatusrelayv1alpha1.HostInterface{Name: name},
+		TotalVFs:      totalVFs,
+	}
+
+	if pf.NumVFs, err = readInt(filepath.Join(deviceDir, "sriov_numvfs")); err != nil {
+		return nil, err
+	}
+	if pf.PCIAddress, err = linkName(deviceDir); err != nil {
+		return nil, err
+	}
+	if pf.VendorID, err = readPCIID(filepath.Join(deviceDir, "vendor")); err != nil {
+		return nil, err
+	}
+	if pf.DeviceID, err = readPCIID(filepath.Join(deviceDir, "device")); err != nil {
+		return nil, err
+	}
+	if pf.Driver, err = linkName(filepath.Join(deviceDir, "driver")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	pf.BondMaster, err = linkName(filepath.Join(ifDir, "master"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return pf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bondDir := filepath.Join(netDir, pf.BondMaster, "bonding")
+	mode, err := readString(filepath.Join(bondDir, "mode"))
+	if errors.Is(err, fs.ErrNotExist) {
+		// The master is not a bond, e.g. a bridge
+		pf.BondMaster = ""
+		return pf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The mode is reported as "<name> <number>"
+	pf.BondMode, _, _ = strings.Cut(mode, " ")
+	if pf.BondMode != bondModeLACP {
+		return pf, nil
+	}
+
+	pf.LACP, err = discoverLACP(bondDir, filepath.Join(ifDir, "bonding_slave"))
+	if err != nil {
+		return nil, err
+	}
+
+	return pf, nil
+}
+
+// discoverLACP returns the LACP state of a slave of an 802.3ad bond.
+func discoverLACP(bondDir, slaveDir string) (*pfstatusrelayv1alpha1.LACPStatus, error) {
+	var err error
+	lacp := &pfstatusrelayv1alpha1.LACPStatus{}
+
+	if lacp.AggregatorID, err = readInt(filepath.Join(slaveDir, "ad_aggregator_id")); err != nil {
+		return nil, err
+	}
+	if lacp.ActorOperPortState, err = readInt(filepath.Join(slaveDir, "ad_actor_oper_port_state")); err != nil {
+		return nil, err
+	}
+	if lacp.PartnerOperPortState, err = readInt(filepath.Join(slaveDir, "ad_partner_oper_port_state")); err != nil {
+		return nil, err
+	}
+	if lacp.PartnerSystem, err = readString(filepath.Join(bondDir, "ad_partner_mac")); err != nil {
+		return nil, err
+	}
+
+	lacp.Synchronized = lacp.ActorOperPortState&portStateInSync == portStateInSync &&
+		lacp.PartnerOperPortState&portStateInSync == portStateInSync
+
+	return lacp, nil
+}
+
+// readString returns the trimmed content of a sysfs attribute.
+func readString(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// readInt returns the content of a sysfs attribute holding a decimal number.
+func readInt(path string) (int32, error) {
+	content, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(content, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid content of %s: %w", path, err)
+	}
+	return int32(value), nil
+}
+
+// readPCIID returns a PCI ID without its 0x prefix.
+func readPCIID(path string) (string, error) {
+	content, err := readString(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(content, "0x"), nil
+}
+
+// linkName returns the name of the file a sysfs link points to.
+func linkName(path string) (string, error) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(target), nil
+}
@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+// fakeSysfs builds a sysfs tree with files relative to root.
+type fakeSysfs struct {
+	root string
+}
+
+func (f *fakeSysfs) file(path, content string) {
+	path = filepath.Join(f.root, path)
+	Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+	Expect(os.WriteFile(path, []byte(content+"\n"), 0o644)).To(Succeed())
+}
+
+func (f *fakeSysfs) link(path, target string) {
+	path = filepath.Join(f.root, path)
+	Expect(os.MkdirAll(filepath.Join(f.root, target), 0o755)).To(Succeed())
+	Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+	Expect(os.Symlink(filepath.Join(f.root, target), path)).To(Succeed())
+}
+
+// pf adds an SR-IOV physical function to the tree.
+func (f *fakeSysfs) pf(name, pciAddress, vendor, device, driver string, totalVFs, numVFs string) {
+	f.link("class/net/"+name+"/device", "devices/pci0000:00/"+pciAddress)
+	f.link("devices/pci0000:00/"+pciAddress+"/driver", "bus/pci/drivers/"+driver)
+	f.file("class/net/"+name+"/device/vendor", vendor)
+	f.file("class/net/"+name+"/device/device", device)
+	f.file("class/net/"+name+"/device/sriov_totalvfs", totalVFs)
+	f.file("class/net/"+name+"/device/sriov_numvfs", numVFs)
+}
+
+var _ = Describe("Discover", func() {
+	var sysfs *fakeSysfs
+
+	BeforeEach(func() {
+		sysfs = &fakeSysfs{root: GinkgoT().TempDir()}
+	})
+
+	It("reports the SR-IOV physical functions only", func() {
+		sysfs.pf("ens1f0", "0000:3b:00.0", "0x8086", "0x159b", "ice", "64", "8")
+		sysfs.file("class/net/lo/mtu", "65536")
+		sysfs.file("class/net/ens1f0v0/device/physfn/vendor", "0x8086")
+
+		pfs, err := Discover(sysfs.root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pfs).To(Equal([]pfstatusrelayv1alpha1.PFStatus{{
+			HostInterface: pfstatusrelayv1alpha1.HostInterface{
+				Name:       "ens1f0",
+				PCIAddress: "0000:3b:00.0",
+				VendorID:   "8086",
+				DeviceID:   "159b",
+				Driver:     "ice",
+			},
+			TotalVFs: 64,
+			NumVFs:   8,
+		}}))
+	})
+
+	It("reports the LACP state of the PFs enslaved to an 802.3ad bond", func() {
+		sysfs.pf("ens1f1", "0000:3b:00.1", "0x8086", "0x159b", "ice", "64", "0")
+		sysfs.pf("ens1f0", "0000:3b:00.0", "0x8086", "0x159b", "ice", "64", "0")
+		sysfs.link("class/net/ens1f0/master", "devices/virtual/net/bond0")
+		sysfs.link("class/net/ens1f1/master", "devices/virtual/net/bond0")
+		sysfs.file("class/net/bond0/bonding/mode", "802.3ad 4")
+		sysfs.file("class/net/bond0/bonding/ad_partner_mac", "00:11:22:33:44:55")
+		sysfs.file("class/net/ens1f0/bonding_slave/ad_aggregator_id", "1")
+		sysfs.file("class/net/ens1f0/bonding_slave/ad_actor_oper_port_state", "61")
+		sysfs.file("class/net/ens1f0/bonding_slave/ad_partner_oper_port_state", "63")
+		sysfs.file("class/net/ens1f1/bonding_slave/ad_aggregator_id", "1")
+		sysfs.file("class/net/ens1f1/bonding_slave/ad_actor_oper_port_state", "69")
+		sysfs.file("class/net/ens1f1/bonding_slave/ad_partner_oper_port_state", "1")
+
+		pfs, err := Discover(sysfs.root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pfs).To(HaveLen(2))
+
+		Expect(pfs[0].Name).To(Equal("ens1f0"))
+		Expect(pfs[0].BondMaster).To(Equal("bond0"))
+		Expect(pfs[0].BondMode).To(Equal("802.3ad"))
+		Expect(pfs[0].LACP).To(Equal(&pfstatusrelayv1alpha1.LACPStatus{
+			AggregatorID:         1,
+			PartnerSystem:        "00:11:22:33:44:55",
+			ActorOperPortState:   61,
+			PartnerOperPortState: 63,
+			Synchronized:         true,
+		}))
+
+		Expect(pfs[1].Name).To(Equal("ens1f1"))
+		Expect(pfs[1].LACP.Synchronized).To(BeFalse())
+	})
+
+	It("does not report the LACP state of other bonds", func() {
+		sysfs.pf("ens1f0", "0000:3b:00.0", "0x8086", "0x159b", "ice", "64", "0")
+		sysfs.link("class/net/ens1f0/master", "devices/virtual/net/bond0")
+		sysfs.file("class/net/bond0/bonding/mode", "active-backup 1")
+
+		pfs, err := Discover(sysfs.root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pfs).To(HaveLen(1))
+		Expect(pfs[0].BondMaster).To(Equal("bond0"))
+		Expect(pfs[0].BondMode).To(Equal("active-backup"))
+		Expect(pfs[0].LACP).To(BeNil())
+	})
+
+	It("ignores masters that are not bonds", func() {
+		sysfs.pf("ens1f0", "0000:3b:00.0", "0x8086", "0x159b", "ice", "64", "0")
+		sysfs.link("class/net/ens1f0/master", "devices/virtual/net/br0")
+
+		pfs, err := Discover(sysfs.root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pfs).To(HaveLen(1))
+		Expect(pfs[0].BondMaster).To(BeEmpty())
+	})
+})
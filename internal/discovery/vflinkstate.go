@@ -0,0 +1,171 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// VF link states, as set with "ip link set <pf> vf <n> state"
+const (
+	vfLinkStateAuto    = 0
+	vfLinkStateEnable  = 1
+	vfLinkStateDisable = 2
+)
+
+// Netlink message and attribute types used to dump the VF link states, from linux/netlink.h and linux/if_link.h
+const (
+	nlmsgHdrLen    = 16
+	ifInfoMsgLen   = 16
+	rtAttrHdrLen   = 4
+	nlmsgError     = 2
+	nlmsgDone      = 3
+	rtmNewLink     = 16
+	iflaIfName     = 3
+	iflaVFInfoList = 22
+	iflaVFInfo     = 1
+	iflaVFLinkStat = 5
+	rtextFilterVF  = 1
+)
+
+// listVFLinkStates returns the link state of the VFs of each interface. It is a variable so that tests can
+// replace it.
+var listVFLinkStates = netlinkVFLinkStates
+
+// vfLinkState summarizes the link states of the VFs of a PF as auto, enable or disable, or mixed when
+// they differ. It returns an empty string for PFs without VFs.
+func vfLinkState(states []uint32) string {
+	if len(states) == 0 {
+		return ""
+	}
+
+	for _, state := range states[1:] {
+		if state != states[0] {
+			return "mixed"
+		}
+	}
+
+	switch states[0] {
+	case vfLinkStateAuto:
+		return "auto"
+	case vfLinkStateEnable:
+		return "enable"
+	case vfLinkStateDisable:
+		return "disable"
+	}
+	return "unknown"
+}
+
+// parseLinkMessages reads the VF link states of the interfaces from RTM_NEWLINK netlink messages.
+// It returns done once the end of the dump is reached.
+func parseLinkMessages(b []byte, states map[string][]uint32) (done bool, err error) {
+	for len(b) >= nlmsgHdrLen {
+		msgLen := int(binary.NativeEndian.Uint32(b[0:4]))
+		msgType := binary.NativeEndian.Uint16(b[4:6])
+		if msgLen < nlmsgHdrLen || msgLen > len(b) {
+			return false, errors.New("invalid netlink message length")
+		}
+
+		switch msgType {
+		case nlmsgDone:
+			return true, nil
+		case nlmsgError:
+			if msgLen >= nlmsgHdrLen+4 {
+				if errno := int32(binary.NativeEndian.Uint32(b[nlmsgHdrLen:])); errno != 0 {
+					return false, fmt.Errorf("netlink error: %w", syscall.Errno(-errno))
+				}
+			}
+		case rtmNewLink:
+			if msgLen >= nlmsgHdrLen+ifInfoMsgLen {
+				name, vfStates := parseLinkAttributes(b[nlmsgHdrLen+ifInfoMsgLen : msgLen])
+				if name != "" {
+					states[name] = vfStates
+				}
+			}
+		}
+
+		b = b[align(msgLen):]
+	}
+
+	return false, nil
+}
+
+// parseLinkAttributes returns the interface name and the VF link states in the attributes of an RTM_NEWLINK message.
+func parseLinkAttributes(b []byte) (string, []uint32) {
+	var name string
+	var vfStates []uint32
+
+	for _, attr := range parseAttributes(b) {
+		value := attr.value
+		switch attr.attrType {
+		case iflaIfName:
+			if len(value) > 0 && value[len(value)-1] == 0 {
+				value = value[:len(value)-1]
+			}
+			name = string(value)
+		case iflaVFInfoList:
+			for _, info := range parseAttributes(value) {
+				if info.attrType != iflaVFInfo {
+					continue
+				}
+				for _, vfAttr := range parseAttributes(info.value) {
+					// struct ifla_vf_link_state { __u32 vf; __u32 link_state; }
+					if vfAttr.attrType == iflaVFLinkStat && len(vfAttr.value) >= 8 {
+						vfStates = append(vfStates, binary.NativeEndian.Uint32(vfAttr.value[4:8]))
+					}
+				}
+			}
+		}
+	}
+
+	return name, vfStates
+}
+
+// attribute is a netlink route attribute.
+type attribute struct {
+	attrType uint16
+	value    []byte
+}
+
+// parseAttributes returns the netlink route attributes of a message payload.
+func parseAttributes(b []byte) []attribute {
+	var attrs []attribute
+	for len(b) >= rtAttrHdrLen {
+		attrLen := int(binary.NativeEndian.Uint16(b[0:2]))
+		if attrLen < rtAttrHdrLen || attrLen > len(b) {
+			break
+		}
+		// The nested flag is not relevant here
+		attrs = append(attrs, attribute{
+			attrType: binary.NativeEndian.Uint16(b[2:4]) & 0x3fff,
+			value:    b[rtAttrHdrLen:attrLen],
+		})
+		if align(attrLen) >= len(b) {
+			break
+		}
+		b = b[align(attrLen):]
+	}
+	return attrs
+}
+
+// align rounds a netlink length up to 4 bytes.
+func align(length int) int {
+	return (length + 3) &^ 3
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// netlinkVFLinkStates dumps the links of the network namespace with their VF information.
+func netlinkVFLinkStates() (map[string][]uint32, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open netlink socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("failed to bind netlink socket: %w", err)
+	}
+
+	// nlmsghdr, ifinfomsg and an IFLA_EXT_MASK attribute requesting the VF information
+	req := make([]byte, unix.NLMSG_HDRLEN+unix.SizeofIfInfomsg+unix.SizeofRtAttr+4)
+	binary.NativeEndian.PutUint32(req[0:4], uint32(len(req)))
+	binary.NativeEndian.PutUint16(req[4:6], unix.RTM_GETLINK)
+	binary.NativeEndian.PutUint16(req[6:8], unix.NLM_F_REQUEST|unix.NLM_F_DUMP)
+	binary.NativeEndian.PutUint32(req[8:12], 1)
+	req[unix.NLMSG_HDRLEN] = unix.AF_UNSPEC
+	attr := req[unix.NLMSG_HDRLEN+unix.SizeofIfInfomsg:]
+	binary.NativeEndian.PutUint16(attr[0:2], unix.SizeofRtAttr+4)
+	binary.NativeEndian.PutUint16(attr[2:4], unix.IFLA_EXT_MASK)
+	binary.NativeEndian.PutUint32(attr[4:8], rtextFilterVF)
+
+	if err := unix.Sendto(fd, req, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("failed to send netlink request: %w", err)
+	}
+
+	states := make(map[string][]uint32)
+	// The messages of links with many VFs are large
+	buf := make([]byte, 1<<20)
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive netlink messages: %w", err)
+		}
+
+		done, err := parseLinkMessages(buf[:n], states)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return states, nil
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import "errors"
+
+// netlinkVFLinkStates is only supported on Linux.
+func netlinkVFLinkStates() (map[string][]uint32, error) {
+	return nil, errors.New("VF link states are only supported on Linux")
+}
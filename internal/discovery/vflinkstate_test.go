@@ -0,0 +1,81 @@
+package discovery
+
+import (
+	"encoding/binary"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// rtAttr encodes a netlink route attribute.
+func rtAttr(attrType uint16, value []byte) []byte {
+	b := make([]byte, align(rtAttrHdrLen+len(value)))
+	binary.NativeEndian.PutUint16(b[0:2], uint16(rtAttrHdrLen+len(value)))
+	binary.NativeEndian.PutUint16(b[2:4], attrType)
+	copy(b[rtAttrHdrLen:], value)
+	return b
+}
+
+// nlMsg encodes a netlink message.
+func nlMsg(msgType uint16, payload []byte) []byte {
+	b := make([]byte, nlmsgHdrLen+len(payload))
+	binary.NativeEndian.PutUint32(b[0:4], uint32(len(b)))
+	binary.NativeEndian.PutUint16(b[4:6], msgType)
+	copy(b[nlmsgHdrLen:], payload)
+	return b
+}
+
+// linkMsg encodes an RTM_NEWLINK message of an interface with VFs in the given link states.
+func linkMsg(name string, states ...uint32) []byte {
+	var vfInfos []byte
+	for vf, state := range states {
+		linkState := make([]byte, 8)
+		binary.NativeEndian.PutUint32(linkState[0:4], uint32(vf))
+		binary.NativeEndian.PutUint32(linkState[4:8], state)
+		vfInfos = append(vfInfos, rtAttr(iflaVFInfo|0x8000, rtAttr(iflaVFLinkStat, linkState))...)
+	}
+
+	payload := make([]byte, ifInfoMsgLen)
+	payload = append(payload, rtAttr(iflaIfName, append([]byte(name), 0))...)
+	if len(states) > 0 {
+		payload = append(payload, rtAttr(iflaVFInfoList|0x8000, vfInfos)...)
+	}
+	return nlMsg(rtmNewLink, payload)
+}
+
+var _ = Describe("VF link states", func() {
+	It("parses the VF link states of a link dump", func() {
+		dump := append(linkMsg("ens1f0", vfLinkStateDisable, vfLinkStateDisable), linkMsg("lo")...)
+		states := make(map[string][]uint32)
+
+		done, err := parseLinkMessages(dump, states)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+		Expect(states).To(Equal(map[string][]uint32{
+			"ens1f0": {vfLinkStateDisable, vfLinkStateDisable},
+			"lo":     nil,
+		}))
+
+		done, err = parseLinkMessages(nlMsg(nlmsgDone, make([]byte, 4)), states)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+	})
+
+	It("returns netlink errors", func() {
+		errno := make([]byte, 4)
+		binary.NativeEndian.PutUint32(errno, uint32(0xffffffff)) // -EPERM
+
+		_, err := parseLinkMessages(nlMsg(nlmsgError, errno), map[string][]uint32{})
+		Expect(err).To(MatchError(ContainSubstring("operation not permitted")))
+	})
+
+	DescribeTable("summarizes the VF link states",
+		func(states []uint32, expected string) {
+			Expect(vfLinkState(states)).To(Equal(expected))
+		},
+		Entry("without VFs", nil, ""),
+		Entry("auto", []uint32{vfLinkStateAuto, vfLinkStateAuto}, "auto"),
+		Entry("forced down", []uint32{vfLinkStateDisable}, "disable"),
+		Entry("mixed", []uint32{vfLinkStateAuto, vfLinkStateDisable}, "mixed"),
+	)
+})
@@ -1,8 +1,150 @@
 package log
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+)
+
+// Log formats.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Level is the minimum level of the records written by Log. It can be changed at runtime.
+var Level = new(slog.LevelVar)
+
+var (
+	// format is the active format of the records, json or text
+	format atomic.Value
+	// defaultLevel and defaultFormat are set by the flags of the operator and restored when the operator config
+	// sets none
+	defaultLevel  = slog.LevelInfo
+	defaultFormat = FormatJSON
 )
 
-var Log = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+func init() {
+	format.Store(FormatJSON)
+}
+
+var Log = slog.New(newHandler())
+
+// Logr returns a logr.Logger writing to the same handler as Log, with the same level and format. It is the logger
+// of controller-runtime, whose reconcilers add the reconcileID and the name and namespace of the reconciled object.
+func Logr() logr.Logger {
+	return logr.FromSlogHandler(Log.Handler())
+}
+
+// FromContext returns the logger of the context set by controller-runtime, or Log when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, err := logr.FromContext(ctx)
+	if err != nil {
+		return Log
+	}
+	return slog.New(logr.ToSlogHandler(logger))
+}
+
+// Configure sets the default level and format of Log, used when the operator config sets none.
+func Configure(level, logFormat string) error {
+	parsedLevel, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	if err = validateFormat(logFormat); err != nil {
+		return err
+	}
+
+	defaultLevel = parsedLevel
+	defaultFormat = logFormat
+	Level.Set(defaultLevel)
+	format.Store(defaultFormat)
+	return nil
+}
+
+// SetLevel sets the minimum level of the records written by Log from its name, one of debug, info, warn
+// or error. An empty name restores the default level.
+func SetLevel(name string) error {
+	if name == "" {
+		Level.Set(defaultLevel)
+		return nil
+	}
+
+	level, err := parseLevel(name)
+	if err != nil {
+		return err
+	}
+	Level.Set(level)
+	return nil
+}
+
+// SetFormat sets the format of the records written by Log, json or text. An empty name restores the default
+// format.
+func SetFormat(name string) error {
+	if name == "" {
+		format.Store(defaultFormat)
+		return nil
+	}
+
+	if err := validateFormat(name); err != nil {
+		return err
+	}
+	format.Store(name)
+	return nil
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, err
+	}
+	return level, nil
+}
+
+func validateFormat(name string) error {
+	if name != FormatJSON && name != FormatText {
+		return fmt.Errorf("unknown log format %q, must be one of %s or %s", name, FormatJSON, FormatText)
+	}
+	return nil
+}
+
+// handler writes the records in the active format.
+type handler struct {
+	json slog.Handler
+	text slog.Handler
+}
+
+func newHandler() *handler {
+	options := &slog.HandlerOptions{Level: Level}
+	return &handler{
+		json: slog.NewJSONHandler(os.Stdout, options),
+		text: slog.NewTextHandler(os.Stdout, options),
+	}
+}
+
+func (h *handler) active() slog.Handler {
+	if format.Load() == FormatText {
+		return h.text
+	}
+	return h.json
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.active().Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	return h.active().Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{json: h.json.WithAttrs(attrs), text: h.text.WithAttrs(attrs)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{json: h.json.WithGroup(name), text: h.text.WithGroup(name)}
+}
@@ -0,0 +1,43 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log", func() {
+	AfterEach(func() {
+		Expect(Configure("info", FormatJSON)).To(Succeed())
+	})
+
+	It("restores the defaults of the flags", func() {
+		Expect(Configure("warn", FormatText)).To(Succeed())
+		Expect(SetLevel("debug")).To(Succeed())
+		Expect(SetFormat(FormatJSON)).To(Succeed())
+		Expect(Log.Enabled(context.Background(), slog.LevelDebug)).To(BeTrue())
+		Expect(format.Load()).To(Equal(FormatJSON))
+
+		Expect(SetLevel("")).To(Succeed())
+		Expect(SetFormat("")).To(Succeed())
+		Expect(Level.Level()).To(Equal(slog.LevelWarn))
+		Expect(format.Load()).To(Equal(FormatText))
+	})
+
+	It("rejects unknown levels and formats", func() {
+		Expect(SetLevel("verbose")).NotTo(Succeed())
+		Expect(SetFormat("yaml")).NotTo(Succeed())
+		Expect(Configure("info", "yaml")).NotTo(Succeed())
+	})
+
+	It("shares the level with the logger of controller-runtime", func() {
+		logger := Logr()
+		Expect(logger.V(1).Enabled()).To(BeFalse())
+
+		Expect(SetLevel("debug")).To(Succeed())
+		Expect(logger.V(1).Enabled()).To(BeTrue())
+		Expect(FromContext(context.Background())).To(Equal(Log))
+	})
+})
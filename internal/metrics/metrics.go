@@ -0,0 +1,158 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the metrics of the operator, served by the metrics server of the manager.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// States of the monitors counted by Monitors.
+const (
+	MonitorStateHealthy  = "healthy"
+	MonitorStateDegraded = "degraded"
+	MonitorStateConflict = "conflict"
+)
+
+// TLSProfile is set to 1 for the TLS profile active on the webhook and metrics servers.
+var TLSProfile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_tls_profile_info",
+	Help: "TLS profile of the webhook and metrics servers of the operator, set to 1 for the active profile.",
+}, []string{"profile", "min_tls_version"})
+
+// Monitors is the number of PFLACPMonitors in each state.
+var Monitors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_monitors",
+	Help: "Number of PFLACPMonitors by state: healthy, degraded or conflict.",
+}, []string{"state"})
+
+// InterfaceClaims is the number of interfaces claimed on each node.
+var InterfaceClaims = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_interface_claims",
+	Help: "Number of interfaces of the node claimed by a PFLACPMonitor.",
+}, []string{"node"})
+
+// DaemonSetDesiredPods is the number of nodes that should run a relay pod of each monitor.
+var DaemonSetDesiredPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_daemonset_desired_pods",
+	Help: "Number of nodes that should run a relay pod of the PFLACPMonitor.",
+}, []string{"namespace", "monitor"})
+
+// DaemonSetReadyPods is the number of nodes running a ready relay pod of each monitor.
+var DaemonSetReadyPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_daemonset_ready_pods",
+	Help: "Number of nodes running a ready relay pod of the PFLACPMonitor.",
+}, []string{"namespace", "monitor"})
+
+// LastSuccessfulReconcile is the time of the last successful reconcile of each monitor.
+var LastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "pf_status_relay_operator_last_successful_reconcile_timestamp_seconds",
+	Help: "Unix time of the last successful reconcile of the PFLACPMonitor.",
+}, []string{"namespace", "monitor"})
+
+// WebhookRejections is the number of PFLACPMonitors rejected by the validating webhook.
+var WebhookRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "pf_status_relay_operator_webhook_rejections_total",
+	Help: "Number of PFLACPMonitor creations and updates rejected by the validating webhook, by reason.",
+}, []string{"reason"})
+
+var (
+	mu sync.Mutex
+	// monitorStates holds the state of each monitor, by namespace/name
+	monitorStates = map[string]string{}
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		TLSProfile,
+		Monitors,
+		InterfaceClaims,
+		DaemonSetDesiredPods,
+		DaemonSetReadyPods,
+		LastSuccessfulReconcile,
+		WebhookRejections,
+	)
+	countMonitors()
+}
+
+// SetTLSProfile records the active TLS profile.
+func SetTLSProfile(profile, minTLSVersion string) {
+	TLSProfile.Reset()
+	TLSProfile.WithLabelValues(profile, minTLSVersion).Set(1)
+}
+
+// SetMonitor records the state of a monitor and the number of nodes that should run and run a ready relay pod.
+func SetMonitor(namespace, name, state string, desired, ready int32) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	monitorStates[namespace+"/"+name] = state
+	countMonitors()
+	DaemonSetDesiredPods.WithLabelValues(namespace, name).Set(float64(desired))
+	DaemonSetReadyPods.WithLabelValues(namespace, name).Set(float64(ready))
+}
+
+// SetReconcileSuccess records the time of the last successful reconcile of a monitor.
+func SetReconcileSuccess(namespace, name string, t time.Time) {
+	LastSuccessfulReconcile.WithLabelValues(namespace, name).Set(float64(t.Unix()))
+}
+
+// DeleteMonitor removes the series of a deleted monitor.
+func DeleteMonitor(namespace, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(monitorStates, namespace+"/"+name)
+	countMonitors()
+	DaemonSetDesiredPods.DeleteLabelValues(namespace, name)
+	DaemonSetReadyPods.DeleteLabelValues(namespace, name)
+	LastSuccessfulReconcile.DeleteLabelValues(namespace, name)
+}
+
+// SetInterfaceClaims records the number of interfaces claimed on a node, removing the series of the node when
+// there is none.
+func SetInterfaceClaims(node string, claims int) {
+	if claims == 0 {
+		InterfaceClaims.DeleteLabelValues(node)
+		return
+	}
+	InterfaceClaims.WithLabelValues(node).Set(float64(claims))
+}
+
+// RecordWebhookRejection counts a PFLACPMonitor rejected by the validating webhook.
+func RecordWebhookRejection(reason string) {
+	WebhookRejections.WithLabelValues(reason).Inc()
+}
+
+// countMonitors sets Monitors from monitorStates. Every state is reported, with zero when no monitor is in it.
+func countMonitors() {
+	counts := map[string]int{
+		MonitorStateHealthy:  0,
+		MonitorStateDegraded: 0,
+		MonitorStateConflict: 0,
+	}
+	for _, state := range monitorStates {
+		counts[state]++
+	}
+	for state, count := range counts {
+		Monitors.WithLabelValues(state).Set(float64(count))
+	}
+}
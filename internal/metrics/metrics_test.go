@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// series returns the value of the series of a gauge or counter with the given labels, and whether it exists.
+func series(name string, labels map[string]string) (float64, bool) {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] == label.GetValue() {
+					matched++
+				}
+			}
+			if matched != len(labels) {
+				continue
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue(), true
+			}
+			return metric.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+// value returns the value of an existing series of a gauge or counter.
+func value(name string, labels map[string]string) float64 {
+	v, found := series(name, labels)
+	Expect(found).To(BeTrue(), "series %s %v not found", name, labels)
+	return v
+}
+
+var _ = Describe("Metrics", func() {
+	It("counts the monitors by state", func() {
+		SetMonitor("default", "monitor-a", MonitorStateHealthy, 3, 2)
+		SetMonitor("default", "monitor-b", MonitorStateConflict, 0, 0)
+		DeferCleanup(func() {
+			DeleteMonitor("default", "monitor-a")
+			DeleteMonitor("default", "monitor-b")
+		})
+
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateHealthy})).To(Equal(1.0))
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateConflict})).To(Equal(1.0))
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateDegraded})).To(Equal(0.0))
+		Expect(value("pf_status_relay_operator_daemonset_desired_pods", map[string]string{"namespace": "default", "monitor": "monitor-a"})).To(Equal(3.0))
+		Expect(value("pf_status_relay_operator_daemonset_ready_pods", map[string]string{"namespace": "default", "monitor": "monitor-a"})).To(Equal(2.0))
+
+		By("moving a monitor to another state")
+		SetMonitor("default", "monitor-b", MonitorStateHealthy, 1, 1)
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateHealthy})).To(Equal(2.0))
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateConflict})).To(Equal(0.0))
+
+		By("deleting a monitor")
+		DeleteMonitor("default", "monitor-a")
+		Expect(value("pf_status_relay_operator_monitors", map[string]string{"state": MonitorStateHealthy})).To(Equal(1.0))
+		_, found := series("pf_status_relay_operator_daemonset_desired_pods", map[string]string{"namespace": "default", "monitor": "monitor-a"})
+		Expect(found).To(BeFalse())
+	})
+
+	It("removes the interface claims of the nodes without claims", func() {
+		SetInterfaceClaims("worker-0", 2)
+		SetInterfaceClaims("worker-1", 1)
+		Expect(value("pf_status_relay_operator_interface_claims", map[string]string{"node": "worker-0"})).To(Equal(2.0))
+		Expect(value("pf_status_relay_operator_interface_claims", map[string]string{"node": "worker-1"})).To(Equal(1.0))
+
+		SetInterfaceClaims("worker-1", 0)
+		_, found := series("pf_status_relay_operator_interface_claims", map[string]string{"node": "worker-1"})
+		Expect(found).To(BeFalse())
+		Expect(value("pf_status_relay_operator_interface_claims", map[string]string{"node": "worker-0"})).To(Equal(2.0))
+	})
+
+	It("counts the webhook rejections by reason", func() {
+		before, _ := series("pf_status_relay_operator_webhook_rejections_total", map[string]string{"reason": "Conflict"})
+		RecordWebhookRejection("Conflict")
+		Expect(value("pf_status_relay_operator_webhook_rejections_total", map[string]string{"reason": "Conflict"})).To(Equal(before + 1))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsprofile
+
+import (
+	"crypto/tls"
+	"sync/atomic"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	openshifttls "github.com/openshift/controller-runtime-common/pkg/tls"
+	"github.com/openshift/pf-status-relay-operator/internal/log"
+	"github.com/openshift/pf-status-relay-operator/internal/metrics"
+)
+
+// Reloader holds the active TLS profile of the servers of the operator. The profile can be changed while the
+// servers run: it applies to the handshakes that follow, without restarting the servers.
+type Reloader struct {
+	profile atomic.Pointer[configv1.TLSProfileSpec]
+}
+
+// NewReloader returns a Reloader with an initial active profile.
+func NewReloader(spec configv1.TLSProfileSpec) *Reloader {
+	r := &Reloader{}
+	r.Set(spec)
+	return r
+}
+
+// Set changes the active TLS profile.
+func (r *Reloader) Set(spec configv1.TLSProfileSpec) {
+	if current := r.profile.Load(); current != nil && equality.Semantic.DeepEqual(*current, spec) {
+		return
+	}
+
+	_, unsupported := openshifttls.NewTLSConfigFromProfile(spec)
+	if len(unsupported) > 0 {
+		log.Log.Info("TLS profile contains ciphers unsupported by Go crypto/tls", "ciphers", unsupported)
+	}
+
+	r.profile.Store(&spec)
+	log.Log.Info("TLS profile applied", "profile", profileType(spec), "minTLSVersion", spec.MinTLSVersion)
+	metrics.SetTLSProfile(profileType(spec), string(spec.MinTLSVersion))
+}
+
+// TLSOpt configures the TLS config of a server with the active profile. The config returned for each handshake is a
+// copy of the server config with the profile active at that time.
+func (r *Reloader) TLSOpt(c *tls.Config) {
+	r.apply(c)
+	c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := c.Clone()
+		config.GetConfigForClient = nil
+		r.apply(config)
+		return config, nil
+	}
+}
+
+// apply sets the active profile on a TLS config.
+func (r *Reloader) apply(c *tls.Config) {
+	opt, _ := openshifttls.NewTLSConfigFromProfile(*r.profile.Load())
+	opt(c)
+}
+
+// profileType returns the name of the predefined profile matching spec, or Custom.
+func profileType(spec configv1.TLSProfileSpec) string {
+	for _, profileType := range []configv1.TLSProfileType{
+		configv1.TLSProfileOldType,
+		configv1.TLSProfileIntermediateType,
+		configv1.TLSProfileModernType,
+	} {
+		if equality.Semantic.DeepEqual(*configv1.TLSProfiles[profileType], spec) {
+			return string(profileType)
+		}
+	}
+
+	return string(configv1.TLSProfileCustomType)
+}
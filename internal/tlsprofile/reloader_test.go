@@ -0,0 +1,36 @@
+package tlsprofile
+
+import (
+	"crypto/tls"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+var _ = Describe("Reloader", func() {
+	It("applies the active profile to the handshakes", func() {
+		reloader := NewReloader(*configv1.TLSProfiles[configv1.TLSProfileIntermediateType])
+		config := &tls.Config{NextProtos: []string{"http/1.1"}}
+		reloader.TLSOpt(config)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+
+		handshake, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handshake.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(handshake.CipherSuites).NotTo(BeEmpty())
+
+		By("changing the profile")
+		reloader.Set(*configv1.TLSProfiles[configv1.TLSProfileModernType])
+		handshake, err = config.GetConfigForClient(&tls.ClientHelloInfo{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handshake.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(handshake.NextProtos).To(Equal([]string{"http/1.1"}))
+		Expect(handshake.GetConfigForClient).To(BeNil())
+	})
+
+	It("names the predefined profiles", func() {
+		Expect(profileType(*configv1.TLSProfiles[configv1.TLSProfileModernType])).To(Equal("Modern"))
+		Expect(profileType(configv1.TLSProfileSpec{MinTLSVersion: configv1.VersionTLS13})).To(Equal("Custom"))
+	})
+})
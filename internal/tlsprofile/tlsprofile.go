@@ -0,0 +1,98 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tlsprofile resolves the TLS profile of the servers of the operator. On OpenShift, the profile of the
+// APIServer is used. On other clusters, the profile is set by the operator config or by flags.
+package tlsprofile
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openshifttls "github.com/openshift/controller-runtime-common/pkg/tls"
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+// APIServerAvailable checks whether the cluster serves the config.openshift.io/v1 APIServer resource holding the
+// TLS profile of the cluster.
+func APIServerAvailable(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(configv1.GroupVersion.WithKind("APIServer").GroupKind(), configv1.GroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to discover the %s API: %w", configv1.GroupVersion, err)
+	}
+
+	return true, nil
+}
+
+// FromFlags returns the TLS profile set by the flags of the operator. The minimum TLS version and the ciphers are
+// only used by the Custom profile, the ciphers defaulting to the ones of the Intermediate profile.
+func FromFlags(profileType string, minVersion string, ciphers []string) (*configv1.TLSSecurityProfile, error) {
+	profile := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileType(profileType)}
+	switch profile.Type {
+	case configv1.TLSProfileOldType, configv1.TLSProfileIntermediateType, configv1.TLSProfileModernType:
+		return profile, nil
+	case configv1.TLSProfileCustomType:
+	default:
+		return nil, fmt.Errorf("unknown TLS profile %q, must be one of Old, Intermediate, Modern or Custom", profileType)
+	}
+
+	version := configv1.TLSProtocolVersion(minVersion)
+	switch version {
+	case configv1.VersionTLS10, configv1.VersionTLS11, configv1.VersionTLS12, configv1.VersionTLS13:
+	default:
+		return nil, fmt.Errorf("unknown minimum TLS version %q, must be one of VersionTLS10, VersionTLS11, VersionTLS12 or VersionTLS13", minVersion)
+	}
+	if len(ciphers) == 0 {
+		ciphers = openshifttls.DefaultTLSCiphers
+	}
+
+	profile.Custom = &configv1.CustomTLSProfile{
+		TLSProfileSpec: configv1.TLSProfileSpec{
+			Ciphers:       ciphers,
+			MinTLSVersion: version,
+		},
+	}
+	return profile, nil
+}
+
+// FetchFallbackProfile returns the TLS profile of the operator config, or fallback when the config does not exist
+// or sets none.
+func FetchFallbackProfile(ctx context.Context, c client.Reader, fallback *configv1.TLSSecurityProfile) (configv1.TLSProfileSpec, error) {
+	config := &pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{}
+	err := c.Get(ctx, client.ObjectKey{Name: pfstatusrelayv1alpha1.OperatorConfigName}, config)
+	// The CRD of the operator config may not be installed yet
+	if client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+		return configv1.TLSProfileSpec{}, fmt.Errorf("failed to get operator config: %w", err)
+	}
+
+	return FallbackProfileSpec(config, fallback)
+}
+
+// FallbackProfileSpec returns the TLS profile of the operator config, or fallback when it sets none.
+func FallbackProfileSpec(config *pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig, fallback *configv1.TLSSecurityProfile) (configv1.TLSProfileSpec, error) {
+	profile := fallback
+	if config.Spec.TLSSecurityProfile != nil {
+		profile = config.Spec.TLSSecurityProfile
+	}
+	return openshifttls.GetTLSProfileSpec(profile)
+}
@@ -0,0 +1,13 @@
+package tlsprofile
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTLSProfile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TLS Profile Suite")
+}
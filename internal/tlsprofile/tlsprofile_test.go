@@ -0,0 +1,64 @@
+package tlsprofile
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pfstatusrelayv1alpha1 "github.com/openshift/pf-status-relay-operator/api/v1alpha1"
+)
+
+var _ = Describe("TLS profile", func() {
+	It("detects the config.openshift.io APIServer", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		Expect(APIServerAvailable(mapper)).To(BeFalse())
+
+		mapper.Add(configv1.GroupVersion.WithKind("APIServer"), meta.RESTScopeRoot)
+		Expect(APIServerAvailable(mapper)).To(BeTrue())
+	})
+
+	It("builds the profiles of the flags", func() {
+		profile, err := FromFlags("Modern", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profile).To(Equal(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType}))
+
+		profile, err = FromFlags("Custom", "VersionTLS13", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profile.Custom.MinTLSVersion).To(Equal(configv1.VersionTLS13))
+		Expect(profile.Custom.Ciphers).To(Equal(configv1.TLSProfiles[configv1.TLSProfileIntermediateType].Ciphers))
+
+		_, err = FromFlags("Strict", "", nil)
+		Expect(err).To(HaveOccurred())
+
+		_, err = FromFlags("Custom", "TLS13", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("prefers the profile of the operator config to the flags", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(pfstatusrelayv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fallback := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		spec, err := FetchFallbackProfile(ctx, c, fallback)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(Equal(*configv1.TLSProfiles[configv1.TLSProfileIntermediateType]))
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&pfstatusrelayv1alpha1.PFStatusRelayOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: pfstatusrelayv1alpha1.OperatorConfigName},
+			Spec: pfstatusrelayv1alpha1.PFStatusRelayOperatorConfigSpec{
+				TLSSecurityProfile: &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType},
+			},
+		}).Build()
+		spec, err = FetchFallbackProfile(ctx, c, fallback)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec).To(Equal(*configv1.TLSProfiles[configv1.TLSProfileModernType]))
+	})
+})